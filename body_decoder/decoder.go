@@ -0,0 +1,135 @@
+package body_decoder
+
+import (
+	"encoding/json"
+	"mime"
+	"strings"
+	"sync"
+)
+
+// Decoder converts response body of some media type to a value which can be compared
+// with compare.Compare (maps with string keys, slices and scalars)
+type Decoder interface {
+	// Decode decodes actual response body, params are parameters of the media type (charset, etc.)
+	Decode(body []byte, params map[string]string) (interface{}, error)
+}
+
+// ExpectedDecoder is implemented by decoders of text formats in which expected body is written too
+// (e.g. XML or YAML). Expected body of the rest of formats is written in JSON.
+type ExpectedDecoder interface {
+	DecodeExpected(body string) (interface{}, error)
+}
+
+// TextExpectedDecoder is implemented by decoders of text formats whose expected body may be written
+// as plain text instead of JSON (e.g. CSV). Such expected bodies are compared with actual ones as strings,
+// as bodies of these formats were compared before they were decoded.
+type TextExpectedDecoder interface {
+	AllowsTextExpected() bool
+}
+
+// AllowsTextExpected reports whether expected body which is not JSON can be compared as plain text for the decoder
+func AllowsTextExpected(decoder Decoder) bool {
+	d, ok := decoder.(TextExpectedDecoder)
+
+	return ok && d.AllowsTextExpected()
+}
+
+// DecoderFunc is an adapter to use ordinary function as a Decoder
+type DecoderFunc func(body []byte, params map[string]string) (interface{}, error)
+
+func (f DecoderFunc) Decode(body []byte, params map[string]string) (interface{}, error) {
+	return f(body, params)
+}
+
+// Registry holds decoders keyed by media type
+type Registry struct {
+	mu       sync.RWMutex
+	decoders map[string]Decoder
+}
+
+var defaultRegistry = NewRegistry()
+
+// NewRegistry creates registry with decoders of JSON, XML, YAML, CSV, MessagePack and form-urlencoded bodies.
+// Protobuf decoder requires a descriptor set, so it should be registered explicitly (see NewProtobufDecoder).
+func NewRegistry() *Registry {
+	r := &Registry{decoders: make(map[string]Decoder)}
+
+	r.Register(JSONDecoder{}, "application/json", "+json")
+	r.Register(XMLDecoder{}, "application/xml", "text/xml", "+xml")
+	r.Register(YAMLDecoder{}, "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml", "+yaml")
+	r.Register(CSVDecoder{}, "text/csv")
+	r.Register(MsgpackDecoder{}, "application/msgpack", "application/x-msgpack", "application/vnd.msgpack")
+	r.Register(FormDecoder{}, "application/x-www-form-urlencoded")
+
+	return r
+}
+
+// Default returns registry used by response body checker
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds decoder to the default registry, see Registry.Register
+func Register(decoder Decoder, mediaTypes ...string) {
+	defaultRegistry.Register(decoder, mediaTypes...)
+}
+
+// Register adds decoder for given media types replacing existing ones.
+// Media type may be a structured syntax suffix (e.g. "+json") to decode all media types having it.
+func (r *Registry) Register(decoder Decoder, mediaTypes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, mediaType := range mediaTypes {
+		r.decoders[strings.ToLower(mediaType)] = decoder
+	}
+}
+
+// Lookup returns decoder for given Content-Type header value and parameters of the media type.
+// Media type is looked up as is, then by its suffix. Media types merely containing "json" or "xml"
+// (e.g. "text/json") are decoded as JSON and XML respectively.
+func (r *Registry) Lookup(contentType string) (Decoder, map[string]string, bool) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// decode bodies with malformed parameters of media type anyway
+		mediaType = strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+		params = map[string]string{}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if decoder, ok := r.decoders[mediaType]; ok {
+		return decoder, params, true
+	}
+
+	if i := strings.LastIndexByte(mediaType, '+'); i != -1 {
+		if decoder, ok := r.decoders[mediaType[i:]]; ok {
+			return decoder, params, true
+		}
+	}
+
+	for _, format := range []string{"json", "xml"} {
+		if strings.Contains(mediaType, format) {
+			if decoder, ok := r.decoders["+"+format]; ok {
+				return decoder, params, true
+			}
+		}
+	}
+
+	return nil, nil, false
+}
+
+// DecodeExpected decodes expected body written for given decoder
+func DecodeExpected(decoder Decoder, body string) (interface{}, error) {
+	if d, ok := decoder.(ExpectedDecoder); ok {
+		return d.DecodeExpected(body)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,156 @@
+package body_decoder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/lamoda/gonkey/compare"
+)
+
+func TestRegistryLookup(t *testing.T) {
+	r := NewRegistry()
+
+	tests := []struct {
+		contentType string
+		decoder     Decoder
+		params      map[string]string
+	}{
+		{contentType: "application/json; charset=utf-8", decoder: JSONDecoder{}, params: map[string]string{"charset": "utf-8"}},
+		{contentType: "application/problem+json", decoder: JSONDecoder{}, params: map[string]string{}},
+		{contentType: "text/json", decoder: JSONDecoder{}, params: map[string]string{}},
+		{contentType: "application/soap+xml", decoder: XMLDecoder{}, params: map[string]string{}},
+		{contentType: "Application/X-YAML", decoder: YAMLDecoder{}, params: map[string]string{}},
+		{contentType: "text/csv; header=absent", decoder: CSVDecoder{}, params: map[string]string{"header": "absent"}},
+		{contentType: "application/msgpack", decoder: MsgpackDecoder{}, params: map[string]string{}},
+		{contentType: "application/x-www-form-urlencoded", decoder: FormDecoder{}, params: map[string]string{}},
+		{contentType: "application/json; charset", decoder: JSONDecoder{}, params: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			decoder, params, ok := r.Lookup(tt.contentType)
+			require.True(t, ok)
+			assert.Equal(t, tt.decoder, decoder)
+			assert.Equal(t, tt.params, params)
+		})
+	}
+
+	for _, contentType := range []string{"", "text/plain", "application/octet-stream"} {
+		_, _, ok := r.Lookup(contentType)
+		assert.False(t, ok, contentType)
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	custom := DecoderFunc(func(body []byte, _ map[string]string) (interface{}, error) {
+		if len(body) == 0 {
+			return nil, errors.New("empty body")
+		}
+
+		return map[string]interface{}{"length": float64(len(body))}, nil
+	})
+	r.Register(custom, "application/vnd.custom", "application/json")
+
+	decoder, _, ok := r.Lookup("application/vnd.custom")
+	require.True(t, ok)
+	v, err := decoder.Decode([]byte("abc"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"length": float64(3)}, v)
+
+	// registered decoder replaces builtin one, suffixes are still decoded by builtin decoder
+	decoder, _, _ = r.Lookup("application/json")
+	_, err = decoder.Decode(nil, nil)
+	assert.EqualError(t, err, "empty body")
+
+	decoder, _, _ = r.Lookup("application/problem+json")
+	assert.Equal(t, JSONDecoder{}, decoder)
+}
+
+func TestDecoders(t *testing.T) {
+	packed, err := msgpack.Marshal(map[string]interface{}{
+		"id":    uint32(7),
+		"price": float32(0.1),
+		"tags":  []string{"a", "b"},
+		"raw":   []byte("bin"),
+		"meta":  map[int]bool{1: true},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		decoder  Decoder
+		params   map[string]string
+		body     string
+		expected string
+	}{
+		{
+			name:     "yaml",
+			decoder:  YAMLDecoder{},
+			body:     "id: 7\nprice: 1.5\ntags: [a, b]\nmeta:\n  1: true\n",
+			expected: `{"id": 7, "price": 1.5, "tags": ["a", "b"], "meta": {"1": true}}`,
+		},
+		{
+			name:     "csv",
+			decoder:  CSVDecoder{},
+			body:     "id,name\n1,foo\n2,\"bar, baz\"\n",
+			expected: `[{"id": "1", "name": "foo"}, {"id": "2", "name": "bar, baz"}]`,
+		},
+		{
+			name:     "csv without header",
+			decoder:  CSVDecoder{},
+			params:   map[string]string{"header": "absent"},
+			body:     "1,foo\n2,bar\n",
+			expected: `[["1", "foo"], ["2", "bar"]]`,
+		},
+		{
+			name:     "msgpack",
+			decoder:  MsgpackDecoder{},
+			body:     string(packed),
+			expected: `{"id": 7, "price": 0.1, "tags": ["a", "b"], "raw": "bin", "meta": {"1": true}}`,
+		},
+		{
+			name:     "form",
+			decoder:  FormDecoder{},
+			body:     "id=7&tag=a&tag=b&name=foo+bar",
+			expected: `{"id": "7", "tag": ["a", "b"], "name": "foo bar"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.decoder.Decode([]byte(tt.body), tt.params)
+			require.NoError(t, err)
+
+			expected, err := JSONDecoder{}.Decode([]byte(tt.expected), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, actual)
+			assert.Empty(t, compare.Compare(expected, actual, compare.Params{}))
+		})
+	}
+}
+
+func TestDecodeExpected(t *testing.T) {
+	v, err := DecodeExpected(MsgpackDecoder{}, `{"id": 7}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": float64(7)}, v)
+
+	v, err = DecodeExpected(YAMLDecoder{}, "id: 7")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": float64(7)}, v)
+
+	_, err = DecodeExpected(CSVDecoder{}, "id,name")
+	assert.Error(t, err)
+}
+
+func TestAllowsTextExpected(t *testing.T) {
+	assert.True(t, AllowsTextExpected(CSVDecoder{}))
+	assert.True(t, AllowsTextExpected(FormDecoder{}))
+	assert.False(t, AllowsTextExpected(JSONDecoder{}))
+	assert.False(t, AllowsTextExpected(MsgpackDecoder{}))
+}
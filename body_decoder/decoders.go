@@ -0,0 +1,191 @@
+package body_decoder
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+
+	"github.com/lamoda/gonkey/xmlparsing"
+)
+
+// JSONDecoder decodes JSON bodies
+type JSONDecoder struct{}
+
+func (JSONDecoder) Decode(body []byte, _ map[string]string) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// XMLDecoder decodes XML bodies the same way as xmlparsing.Parse does
+type XMLDecoder struct{}
+
+func (XMLDecoder) Decode(body []byte, _ map[string]string) (interface{}, error) {
+	return xmlparsing.Parse(string(body))
+}
+
+func (d XMLDecoder) DecodeExpected(body string) (interface{}, error) {
+	return d.Decode([]byte(body), nil)
+}
+
+// YAMLDecoder decodes YAML bodies, values have the same types as decoded from JSON
+type YAMLDecoder struct{}
+
+func (YAMLDecoder) Decode(body []byte, _ map[string]string) (interface{}, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+
+	return Normalize(v), nil
+}
+
+func (d YAMLDecoder) DecodeExpected(body string) (interface{}, error) {
+	return d.Decode([]byte(body), nil)
+}
+
+// CSVDecoder decodes CSV bodies to array of rows. Rows are maps keyed by names of columns
+// from the first line, or arrays of values if media type has "header=absent" parameter.
+type CSVDecoder struct{}
+
+func (CSVDecoder) Decode(body []byte, params map[string]string) (interface{}, error) {
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]interface{}, 0, len(records))
+	if params["header"] == "absent" {
+		for _, record := range records {
+			rows = append(rows, stringsToInterfaces(record))
+		}
+
+		return rows, nil
+	}
+
+	if len(records) == 0 {
+		return rows, nil
+	}
+
+	header := records[0]
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(header))
+		for i, name := range header {
+			row[name] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (CSVDecoder) AllowsTextExpected() bool {
+	return true
+}
+
+// MsgpackDecoder decodes MessagePack bodies, values have the same types as decoded from JSON
+type MsgpackDecoder struct{}
+
+func (MsgpackDecoder) Decode(body []byte, _ map[string]string) (interface{}, error) {
+	dec := msgpack.NewDecoder(bytes.NewReader(body))
+	// keys of maps are not necessarily strings in MessagePack
+	dec.SetMapDecoder(func(d *msgpack.Decoder) (interface{}, error) {
+		return d.DecodeUntypedMap()
+	})
+
+	v, err := dec.DecodeInterface()
+	if err != nil {
+		return nil, err
+	}
+
+	return Normalize(v), nil
+}
+
+// FormDecoder decodes application/x-www-form-urlencoded bodies to map,
+// fields having several values are decoded to arrays
+type FormDecoder struct{}
+
+func (FormDecoder) Decode(body []byte, _ map[string]string) (interface{}, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	form := make(map[string]interface{}, len(values))
+	for name, v := range values {
+		if len(v) == 1 {
+			form[name] = v[0]
+		} else {
+			form[name] = stringsToInterfaces(v)
+		}
+	}
+
+	return form, nil
+}
+
+func (FormDecoder) AllowsTextExpected() bool {
+	return true
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	res := make([]interface{}, len(values))
+	for i, v := range values {
+		res[i] = v
+	}
+
+	return res
+}
+
+// Normalize converts decoded value to types produced by encoding/json:
+// maps with string keys, []interface{}, float64, string, bool and nil
+func Normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, float64:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+
+	ref := reflect.ValueOf(value)
+	switch ref.Kind() {
+	case reflect.Map:
+		res := make(map[string]interface{}, ref.Len())
+		iter := ref.MapRange()
+		for iter.Next() {
+			res[fmt.Sprintf("%v", iter.Key().Interface())] = Normalize(iter.Value().Interface())
+		}
+
+		return res
+	case reflect.Slice, reflect.Array:
+		res := make([]interface{}, ref.Len())
+		for i := range res {
+			res[i] = Normalize(ref.Index(i).Interface())
+		}
+
+		return res
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(ref.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(ref.Uint())
+	case reflect.Float32:
+		// keep the shortest representation of float32 value (0.1 instead of 0.10000000149011612)
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(ref.Float(), 'g', -1, 32), 64)
+
+		return f
+	default:
+		return value
+	}
+}
@@ -0,0 +1,117 @@
+package body_decoder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtobufMediaTypes are media types ProtobufDecoder is usually registered for
+var ProtobufMediaTypes = []string{"application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf"}
+
+// ProtobufDecoder decodes protobuf messages described by a descriptor set
+// (made by `protoc --include_imports --descriptor_set_out=...`).
+// Messages are decoded the way they are mapped to JSON, fields are named as in .proto files.
+//
+// Type of the message is taken from "proto" or "messageType" parameter of the media type
+// (application/x-protobuf; proto=package.Message), default message type is used if they are absent.
+type ProtobufDecoder struct {
+	files          *protoregistry.Files
+	types          *dynamicpb.Types
+	defaultMessage string
+}
+
+// NewProtobufDecoder creates decoder of messages from serialized FileDescriptorSet
+func NewProtobufDecoder(descriptorSet []byte, defaultMessage string) (*ProtobufDecoder, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(descriptorSet, &set); err != nil {
+		return nil, fmt.Errorf("can't parse descriptor set: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+
+	d := &ProtobufDecoder{
+		files:          files,
+		types:          dynamicpb.NewTypes(files),
+		defaultMessage: defaultMessage,
+	}
+	if defaultMessage != "" {
+		if _, err := d.findMessage(defaultMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// NewProtobufDecoderFromFile creates decoder of messages from file with serialized FileDescriptorSet
+func NewProtobufDecoderFromFile(path, defaultMessage string) (*ProtobufDecoder, error) {
+	descriptorSet, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProtobufDecoder(descriptorSet, defaultMessage)
+}
+
+func (d *ProtobufDecoder) Decode(body []byte, params map[string]string) (interface{}, error) {
+	name := d.defaultMessage
+	for _, param := range []string{"proto", "messagetype"} {
+		if params[param] != "" {
+			name = params[param]
+
+			break
+		}
+	}
+	if name == "" {
+		return nil, errors.New("type of protobuf message is unknown: set proto parameter of Content-Type or default message type")
+	}
+
+	messageType, err := d.findMessage(name)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := dynamicpb.NewMessage(messageType)
+	if err := (proto.UnmarshalOptions{Resolver: d.types}).Unmarshal(body, msg); err != nil {
+		return nil, err
+	}
+
+	data, err := protojson.MarshalOptions{UseProtoNames: true, Resolver: d.types}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (d *ProtobufDecoder) findMessage(name string) (protoreflect.MessageDescriptor, error) {
+	desc, err := d.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("protobuf message %s not found: %w", name, err)
+	}
+
+	messageType, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a protobuf message", name)
+	}
+
+	return messageType, nil
+}
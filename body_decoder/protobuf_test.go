@@ -0,0 +1,136 @@
+package body_decoder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func orderDescriptorSet(t *testing.T) *descriptorpb.FileDescriptorSet {
+	t.Helper()
+
+	type (
+		fieldType  = descriptorpb.FieldDescriptorProto_Type
+		fieldLabel = descriptorpb.FieldDescriptorProto_Label
+	)
+	field := func(name string, number int32, typ fieldType, label fieldLabel) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+
+	items := field("items", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_LABEL_REPEATED)
+	items.TypeName = proto.String(".shop.Item")
+
+	return &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("shop.proto"),
+		Package: proto.String("shop"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Order"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("order_id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional),
+					field("status", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+					items,
+				},
+			},
+			{
+				Name: proto.String("Item"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("sku", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+				},
+			},
+		},
+	}}}
+}
+
+func writeDescriptorSet(t *testing.T) (string, []byte) {
+	t.Helper()
+
+	data, err := proto.Marshal(orderDescriptorSet(t))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "shop.pb")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path, data
+}
+
+// marshalOrder encodes message given in JSON mapping to protobuf wire format
+func marshalOrder(t *testing.T, messageName, message string) []byte {
+	t.Helper()
+
+	files, err := protodesc.NewFiles(orderDescriptorSet(t))
+	require.NoError(t, err)
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(messageName))
+	require.NoError(t, err)
+
+	msg := dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+	require.NoError(t, protojson.Unmarshal([]byte(message), msg))
+
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestProtobufDecoder(t *testing.T) {
+	path, _ := writeDescriptorSet(t)
+	decoder, err := NewProtobufDecoderFromFile(path, "shop.Order")
+	require.NoError(t, err)
+
+	body := marshalOrder(t, "shop.Order", `{"order_id": 7, "status": "new", "items": [{"sku": "A"}]}`)
+	v, err := decoder.Decode(body, map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"order_id": float64(7),
+		"status":   "new",
+		"items":    []interface{}{map[string]interface{}{"sku": "A"}},
+	}, v)
+
+	// message type given in media type takes precedence over default one
+	registry := NewRegistry()
+	registry.Register(decoder, ProtobufMediaTypes...)
+	found, params, ok := registry.Lookup("application/x-protobuf; messageType=shop.Item")
+	require.True(t, ok)
+
+	v, err = found.Decode(marshalOrder(t, "shop.Item", `{"sku": "B"}`), params)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"sku": "B"}, v)
+}
+
+func TestProtobufDecoderErrors(t *testing.T) {
+	_, data := writeDescriptorSet(t)
+
+	_, err := NewProtobufDecoder([]byte("not a descriptor set"), "")
+	assert.Error(t, err)
+
+	_, err = NewProtobufDecoder(data, "shop.Unknown")
+	assert.ErrorIs(t, err, protoregistry.NotFound)
+	assert.ErrorContains(t, err, "protobuf message shop.Unknown not found")
+
+	decoder, err := NewProtobufDecoder(data, "")
+	require.NoError(t, err)
+
+	_, err = decoder.Decode(nil, map[string]string{})
+	assert.EqualError(t, err, "type of protobuf message is unknown: set proto parameter of Content-Type or default message type")
+
+	_, err = decoder.Decode([]byte{0xff}, map[string]string{"proto": "shop.Order"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,146 @@
+package response_binary
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseBinaryChecker struct{}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseBinaryChecker{}
+}
+
+func (c *ResponseBinaryChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	assertion, ok := t.GetBinaryAssertion(result.ResponseStatusCode)
+	if !ok {
+		return nil, nil
+	}
+
+	body := []byte(result.ResponseBody)
+
+	var errs []error
+	if assertion.SHA256 != "" {
+		sum := sha256.Sum256(body)
+		errs = append(errs, checkHash("sha256", assertion.SHA256, sum[:])...)
+	}
+	if assertion.MD5 != "" {
+		sum := md5.Sum(body)
+		errs = append(errs, checkHash("md5", assertion.MD5, sum[:])...)
+	}
+	errs = append(errs, checkSize(assertion, len(body))...)
+	if assertion.Type != "" {
+		errs = append(errs, checkType(assertion.Type, body)...)
+	}
+	if assertion.Image != nil {
+		errs = append(errs, checkImage(assertion.Image, body)...)
+	}
+	if assertion.File != "" {
+		expected, err := os.ReadFile(assertion.File)
+		if err != nil {
+			return nil, fmt.Errorf("invalid binary assertion for test %s (status %d): %w", t.GetName(), result.ResponseStatusCode, err)
+		}
+		errs = append(errs, checkFile(assertion.File, expected, body)...)
+	}
+
+	return errs, nil
+}
+
+func checkHash(name, expected string, sum []byte) []error {
+	actual := hex.EncodeToString(sum)
+	if strings.EqualFold(expected, actual) {
+		return nil
+	}
+
+	return []error{models.NewBinaryError("%s of response body %s does not match expected %s", name, actual, expected)}
+}
+
+func checkSize(assertion models.BinaryAssertion, size int) []error {
+	var errs []error
+	if assertion.Size != nil && size != *assertion.Size {
+		errs = append(errs, models.NewBinaryError("response body size %d does not match expected %d", size, *assertion.Size))
+	}
+	if assertion.MinSize != nil && size < *assertion.MinSize {
+		errs = append(errs, models.NewBinaryError("response body size %d is less than minimum %d", size, *assertion.MinSize))
+	}
+	if assertion.MaxSize != nil && size > *assertion.MaxSize {
+		errs = append(errs, models.NewBinaryError("response body size %d is greater than maximum %d", size, *assertion.MaxSize))
+	}
+
+	return errs
+}
+
+// checkType compares media type detected by the body content the way http.DetectContentType does
+func checkType(expected string, body []byte) []error {
+	actual := detectMediaType(body)
+	if len(compare.Compare(expected, actual, compare.Params{})) == 0 {
+		return nil
+	}
+
+	return []error{models.NewBinaryError("response body type %s does not match expected %s", actual, expected)}
+}
+
+func checkImage(expected *models.ImageAssertion, body []byte) []error {
+	config, format, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return []error{models.NewBinaryError("response body is not an image: %s", err)}
+	}
+
+	var errs []error
+	if expected.Format != "" && !strings.EqualFold(expected.Format, format) {
+		errs = append(errs, models.NewBinaryError("image format %s does not match expected %s", format, expected.Format))
+	}
+	if expected.Width != nil && config.Width != *expected.Width {
+		errs = append(errs, models.NewBinaryError("image width %d does not match expected %d", config.Width, *expected.Width))
+	}
+	if expected.Height != nil && config.Height != *expected.Height {
+		errs = append(errs, models.NewBinaryError("image height %d does not match expected %d", config.Height, *expected.Height))
+	}
+
+	return errs
+}
+
+func checkFile(path string, expected, actual []byte) []error {
+	if bytes.Equal(expected, actual) {
+		return nil
+	}
+
+	offset := 0
+	for offset < len(expected) && offset < len(actual) && expected[offset] == actual[offset] {
+		offset++
+	}
+
+	return []error{models.NewBinaryError(
+		"response body differs from file %s at byte %d (size %d, expected %d)",
+		path,
+		offset,
+		len(actual),
+		len(expected),
+	)}
+}
+
+// detectMediaType returns media type of the body detected by its content without parameters
+func detectMediaType(body []byte) string {
+	detected := http.DetectContentType(body)
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		return detected
+	}
+
+	return mediaType
+}
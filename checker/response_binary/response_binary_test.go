@@ -0,0 +1,128 @@
+package response_binary
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+
+	return buf.Bytes()
+}
+
+func newTest(assertion models.BinaryAssertion) *yaml_file.Test {
+	return &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{
+		ResponseBinary: map[int]models.BinaryAssertion{200: assertion},
+	}}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func check(t *testing.T, assertion models.BinaryAssertion, body []byte) []string {
+	errs, err := NewChecker().Check(newTest(assertion), &models.Result{ResponseStatusCode: 200, ResponseBody: string(body)})
+	require.NoError(t, err)
+
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+
+	return messages
+}
+
+func TestCheckPasses(t *testing.T) {
+	body := pngImage(t, 3, 2)
+	sha256Sum := sha256.Sum256(body)
+	md5Sum := md5.Sum(body)
+
+	file := filepath.Join(t.TempDir(), "thumb.png")
+	require.NoError(t, os.WriteFile(file, body, 0o644))
+
+	errs := check(t, models.BinaryAssertion{
+		SHA256:  hex.EncodeToString(sha256Sum[:]),
+		MD5:     hex.EncodeToString(md5Sum[:]),
+		Size:    intPtr(len(body)),
+		MinSize: intPtr(1),
+		MaxSize: intPtr(1024),
+		Type:    "$matchRegexp(^image/)",
+		Image:   &models.ImageAssertion{Format: "PNG", Width: intPtr(3), Height: intPtr(2)},
+		File:    file,
+	}, body)
+	assert.Empty(t, errs)
+}
+
+func TestCheckFails(t *testing.T) {
+	body := pngImage(t, 3, 2)
+
+	changed := append([]byte{}, body...)
+	changed[20]++
+	file := filepath.Join(t.TempDir(), "thumb.png")
+	require.NoError(t, os.WriteFile(file, append(changed, 0), 0o644))
+
+	errs := check(t, models.BinaryAssertion{
+		SHA256:  "00",
+		Size:    intPtr(1),
+		MaxSize: intPtr(10),
+		Type:    "application/pdf",
+		Image:   &models.ImageAssertion{Format: "jpeg", Width: intPtr(4), Height: intPtr(1)},
+		File:    file,
+	}, body)
+
+	sha256Sum := sha256.Sum256(body)
+	assert.Equal(t, []string{
+		"sha256 of response body " + hex.EncodeToString(sha256Sum[:]) + " does not match expected 00",
+		fmt.Sprintf("response body size %d does not match expected 1", len(body)),
+		fmt.Sprintf("response body size %d is greater than maximum 10", len(body)),
+		"response body type image/png does not match expected application/pdf",
+		"image format png does not match expected jpeg",
+		"image width 3 does not match expected 4",
+		"image height 2 does not match expected 1",
+		fmt.Sprintf("response body differs from file %s at byte 20 (size %d, expected %d)", file, len(body), len(body)+1),
+	}, errs)
+}
+
+func TestCheckNotImage(t *testing.T) {
+	errs := check(t, models.BinaryAssertion{
+		MinSize: intPtr(10),
+		Image:   &models.ImageAssertion{},
+	}, []byte("text"))
+
+	assert.Equal(t, []string{
+		"response body size 4 is less than minimum 10",
+		"response body is not an image: image: unknown format",
+	}, errs)
+}
+
+func TestCheckMissingFile(t *testing.T) {
+	test := newTest(models.BinaryAssertion{File: filepath.Join(t.TempDir(), "missing.png")})
+
+	_, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200})
+	assert.Error(t, err)
+}
+
+func TestCheckOtherStatus(t *testing.T) {
+	errs := check(t, models.BinaryAssertion{Size: intPtr(1)}, nil)
+	assert.Len(t, errs, 1)
+
+	errs2, err := NewChecker().Check(newTest(models.BinaryAssertion{Size: intPtr(1)}), &models.Result{ResponseStatusCode: 404})
+	require.NoError(t, err)
+	assert.Empty(t, errs2)
+}
@@ -1,123 +1,117 @@
 package response_body
 
 import (
-	"encoding/json"
 	"fmt"
-	"strings"
 
+	"github.com/lamoda/gonkey/body_decoder"
 	"github.com/lamoda/gonkey/checker"
 	"github.com/lamoda/gonkey/compare"
 	"github.com/lamoda/gonkey/models"
-	"github.com/lamoda/gonkey/xmlparsing"
 )
 
-type ResponseBodyChecker struct{}
+type ResponseBodyChecker struct {
+	// diff of expected and actual bodies is set to results of failed tests
+	diff bool
+}
 
 func NewChecker() checker.CheckerInterface {
 	return &ResponseBodyChecker{}
 }
 
+// NewCheckerWithDiff creates checker setting diff of expected and actual bodies to results of failed tests,
+// empty format means that diff is disabled
+func NewCheckerWithDiff(format compare.DiffFormat) checker.CheckerInterface {
+	return &ResponseBodyChecker{diff: format != ""}
+}
+
 func (c *ResponseBodyChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
 	var errs []error
 	var foundResponse bool
 	if expectedBody, ok := t.GetResponse(result.ResponseStatusCode); ok {
 		foundResponse = true
+		decoder, mediaTypeParams, decodable := body_decoder.Default().Lookup(result.ResponseContentType)
 		switch {
-		case strings.Contains(result.ResponseContentType, "json") && expectedBody != "":
-			checkErrs, err := compareJsonBody(t, expectedBody, result)
-			if err != nil {
-				return nil, err
-			}
-			errs = append(errs, checkErrs...)
-		case strings.Contains(result.ResponseContentType, "xml") && expectedBody != "":
-			checkErrs, err := compareXmlBody(t, expectedBody, result)
+		case decodable && expectedBody != "":
+			checkErrs, err := c.compareDecodedBody(t, expectedBody, decoder, mediaTypeParams, result)
 			if err != nil {
 				return nil, err
 			}
 			errs = append(errs, checkErrs...)
 		default:
-			compareErrs := compare.Compare(expectedBody, result.ResponseBody, compare.Params{})
-			for _, err := range compareErrs {
-				errs = append(errs, models.NewBodyErrorWithCause(err, "%s", err))
-			}
+			errs = append(errs, compareTextBody(expectedBody, result)...)
 		}
 
 	}
-	if !foundResponse {
-		expectedCodes := getExpectedStatusCodes(t.GetResponses())
-		err := models.NewStatusCodeError(expectedCodes[0], result.ResponseStatusCode)
+	// tests may check only headers or XPath assertions of the response
+	if expectedCodes := models.ExpectedStatusCodes(t); !foundResponse && len(expectedCodes) > 0 {
+		err := models.NewStatusCodesError(expectedCodes, result.ResponseStatusCode)
 		errs = append(errs, err)
 	}
 
 	return errs, nil
 }
 
-func getExpectedStatusCodes(responses map[int]string) []int {
-	codes := make([]int, 0, len(responses))
-	for code := range responses {
-		codes = append(codes, code)
+func (c *ResponseBodyChecker) compareDecodedBody(
+	t models.TestInterface,
+	expectedBody string,
+	decoder body_decoder.Decoder,
+	mediaTypeParams map[string]string,
+	result *models.Result,
+) ([]error, error) {
+	expected, err := body_decoder.DecodeExpected(decoder, expectedBody)
+	if err != nil && body_decoder.AllowsTextExpected(decoder) {
+		return compareTextBody(expectedBody, result), nil
 	}
-
-	return codes
-}
-
-func compareJsonBody(t models.TestInterface, expectedBody string, result *models.Result) ([]error, error) {
-	var expected interface{}
-	if err := json.Unmarshal([]byte(expectedBody), &expected); err != nil {
+	if err != nil {
 		return nil, fmt.Errorf(
-			"invalid JSON in response for test %s (status %d): %s",
+			"invalid body in response for test %s (status %d): %s",
 			t.GetName(),
 			result.ResponseStatusCode,
 			err.Error(),
 		)
 	}
 
-	var actual interface{}
-	if err := json.Unmarshal([]byte(result.ResponseBody), &actual); err != nil {
+	actual, err := decoder.Decode([]byte(result.ResponseBody), mediaTypeParams)
+	if err != nil {
 		return []error{models.NewBodyErrorWithCause(err, "could not parse response")}, nil
 	}
 
-	params := compare.Params{
-		IgnoreValues:         !t.NeedsCheckingValues(),
-		IgnoreArraysOrdering: t.IgnoreArraysOrdering(),
-		DisallowExtraFields:  t.DisallowExtraFields(),
-	}
+	return c.compareBody(t, expected, actual, result), nil
+}
+
+// compareTextBody compares bodies as plain text
+func compareTextBody(expectedBody string, result *models.Result) []error {
+	captures, compareErrs := compare.CompareWithCaptures(expectedBody, result.ResponseBody, compare.Params{})
+	result.Captures = captures
 
-	compareErrs := compare.Compare(expected, actual, params)
 	errs := make([]error, 0, len(compareErrs))
 	for _, err := range compareErrs {
-		errs = append(errs, models.NewBodyErrorWithCause(err, "%s", err))
+		errs = append(errs, models.NewBodyMismatchError(err))
 	}
 
-	return errs, nil
+	return errs
 }
 
-func compareXmlBody(t models.TestInterface, expectedBody string, result *models.Result) ([]error, error) {
-	expected, err := xmlparsing.Parse(expectedBody)
-	if err != nil {
-		return nil, fmt.Errorf(
-			"invalid XML in response for test %s (status %d): %s",
-			t.GetName(),
-			result.ResponseStatusCode,
-			err.Error(),
-		)
-	}
+func (c *ResponseBodyChecker) compareBody(t models.TestInterface, expected, actual interface{}, result *models.Result) []error {
+	params := t.GetComparisonParams()
+	captures, compareErrs := compare.CompareWithCaptures(expected, actual, params)
+	if len(compareErrs) == 0 {
+		result.Captures = captures
 
-	actual, err := xmlparsing.Parse(result.ResponseBody)
-	if err != nil {
-		return []error{models.NewBodyErrorWithCause(err, "could not parse response")}, nil
-	}
-	params := compare.Params{
-		IgnoreValues:         !t.NeedsCheckingValues(),
-		IgnoreArraysOrdering: t.IgnoreArraysOrdering(),
-		DisallowExtraFields:  t.DisallowExtraFields(),
+		return nil
 	}
 
-	compareErrs := compare.Compare(expected, actual, params)
 	errs := make([]error, 0, len(compareErrs))
 	for _, err := range compareErrs {
-		errs = append(errs, models.NewBodyErrorWithCause(err, "%s", err))
+		errs = append(errs, models.NewBodyMismatchError(err))
 	}
 
-	return errs, nil
+	if !c.diff {
+		return errs
+	}
+	if diff, err := compare.NewDiff(expected, actual, params); err == nil {
+		result.BodyDiff = diff
+	}
+
+	return errs
 }
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/fatih/color"
 	"github.com/kylelemons/godebug/pretty"
 
 	"github.com/lamoda/gonkey/checker"
@@ -95,7 +94,7 @@ func (c *ResponseDbChecker) check(
 	})
 
 	for _, err := range errs {
-		errors = append(errors, models.NewDatabaseErrorWithIdentifier(queryIndex, "%s", err.Error()))
+		errors = append(errors, models.NewDatabaseMismatchError(queryIndex, err))
 	}
 
 	return errors, nil
@@ -126,11 +125,11 @@ func compareDbResponseLength(expected, actual []string, query interface{}) error
 
 	if len(expected) != len(actual) {
 		err = fmt.Errorf(
-			"quantity of items in database do not match (-expected: %s +actual: %s)\n     test query:\n%s\n    result diff:\n%s",
-			color.CyanString("%v", len(expected)),
-			color.CyanString("%v", len(actual)),
-			color.CyanString("%v", query),
-			color.CyanString("%v", pretty.Compare(expected, actual)),
+			"quantity of items in database do not match (-expected: %v +actual: %v)\n     test query:\n%v\n    result diff:\n%v",
+			len(expected),
+			len(actual),
+			query,
+			pretty.Compare(expected, actual),
 		)
 	}
 
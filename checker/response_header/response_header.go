@@ -2,6 +2,7 @@ package response_header
 
 import (
 	"net/textproto"
+	"sort"
 
 	"github.com/lamoda/gonkey/checker"
 	"github.com/lamoda/gonkey/compare"
@@ -20,26 +21,86 @@ func (c *ResponseHeaderChecker) Check(t models.TestInterface, result *models.Res
 		return nil, nil
 	}
 
-	var errs []error
+	params := t.GetHeadersComparisonParams()
+
+	expected := make(map[string]models.HeaderValue, len(expectedHeaders))
 	for k, v := range expectedHeaders {
-		k = textproto.CanonicalMIMEHeaderKey(k)
+		expected[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+
+	var errs []error
+	for _, k := range sortedKeys(expected) {
+		v := expected[k]
 		actualValues, ok := result.ResponseHeaders[k]
-		if !ok {
+		switch {
+		case v.IsAbsent() && ok:
+			errs = append(errs, models.NewHeaderError("response includes header %s which is expected to be absent", k))
+		case v.IsAbsent():
+		case !ok:
 			errs = append(errs, models.NewHeaderError("response does not include expected header %s", k))
+		case v.IsList:
+			if !matchAll(v.Values, actualValues, params.IgnoreValuesOrdering) {
+				errs = append(errs, models.NewHeaderError("response header %s values %v do not match expected %s", k, actualValues, v))
+			}
+		case !matchAny(v.Value(), actualValues):
+			errs = append(errs, models.NewHeaderError("response header %s value does not match expected %s", k, v))
+		}
+	}
 
-			continue
+	if params.DisallowExtraHeaders {
+		actual := make([]string, 0, len(result.ResponseHeaders))
+		for k := range result.ResponseHeaders {
+			actual = append(actual, k)
 		}
-		found := false
-		for _, actualValue := range actualValues {
-			e := compare.Compare(v, actualValue, compare.Params{})
-			if len(e) == 0 {
-				found = true
+		sort.Strings(actual)
+
+		for _, k := range actual {
+			if _, ok := expected[textproto.CanonicalMIMEHeaderKey(k)]; !ok {
+				errs = append(errs, models.NewHeaderError("response includes unexpected header %s", k))
 			}
 		}
-		if !found {
-			errs = append(errs, models.NewHeaderError("response header %s value does not match expected %s", k, v))
-		}
 	}
 
 	return errs, nil
 }
+
+// matchAny reports whether any of actual values matches expected one
+func matchAny(expected string, actualValues []string) bool {
+	for _, actualValue := range actualValues {
+		if len(compare.Compare(expected, actualValue, compare.Params{})) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchAll reports whether all actual values match expected ones
+func matchAll(expected, actualValues []string, ignoreOrdering bool) bool {
+	errs := compare.Compare(
+		stringsToInterfaces(expected),
+		stringsToInterfaces(actualValues),
+		compare.Params{IgnoreArraysOrdering: ignoreOrdering},
+	)
+
+	return len(errs) == 0
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	res := make([]interface{}, len(values))
+	for i, v := range values {
+		res[i] = v
+	}
+
+	return res
+}
+
+func sortedKeys(m map[string]models.HeaderValue) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
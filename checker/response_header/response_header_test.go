@@ -12,10 +12,10 @@ import (
 
 func TestCheckShouldMatchSubset(t *testing.T) {
 	test := &yaml_file.Test{
-		ResponseHeaders: map[int]map[string]string{
+		ResponseHeaders: map[int]map[string]models.HeaderValue{
 			200: {
-				"content-type": "application/json",
-				"ACCEPT":       "text/html",
+				"content-type": models.NewHeaderValue("application/json"),
+				"ACCEPT":       models.NewHeaderValue("text/html"),
 			},
 		},
 	}
@@ -43,10 +43,10 @@ func TestCheckShouldMatchSubset(t *testing.T) {
 
 func TestCheckWhenNotMatchedShouldReturnError(t *testing.T) {
 	test := &yaml_file.Test{
-		ResponseHeaders: map[int]map[string]string{
+		ResponseHeaders: map[int]map[string]models.HeaderValue{
 			200: {
-				"content-type": "application/json",
-				"accept":       "text/html",
+				"content-type": models.NewHeaderValue("application/json"),
+				"accept":       models.NewHeaderValue("text/html"),
 			},
 		},
 	}
@@ -75,3 +75,112 @@ func TestCheckWhenNotMatchedShouldReturnError(t *testing.T) {
 	assert.Contains(t, errs[0].Error(), "response does not include expected header Content-Type")
 	assert.Contains(t, errs[1].Error(), "response header Accept value does not match expected text/html")
 }
+
+func TestCheckAbsentHeaders(t *testing.T) {
+	test := &yaml_file.Test{
+		ResponseHeaders: map[int]map[string]models.HeaderValue{
+			200: {
+				"Server":  models.NewHeaderValue(models.AbsentHeader),
+				"x-debug": models.NewHeaderValue(models.AbsentHeader),
+			},
+		},
+	}
+
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseHeaders: map[string][]string{
+			"Server": {"nginx"},
+		},
+	}
+
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err)
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "response includes header Server which is expected to be absent")
+}
+
+func TestCheckListValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected models.HeaderValue
+		params   models.HeadersComparisonParams
+		fails    bool
+	}{
+		{
+			name:     "same values in the same order",
+			expected: models.NewHeaderValues("$matchRegexp(^sid=\\w+$)", "lang=en"),
+		},
+		{
+			name:     "same values in other order",
+			expected: models.NewHeaderValues("lang=en", "sid=abc"),
+			fails:    true,
+		},
+		{
+			name:     "same values in other order ignoring ordering",
+			expected: models.NewHeaderValues("lang=en", "sid=abc"),
+			params:   models.HeadersComparisonParams{IgnoreValuesOrdering: true},
+		},
+		{
+			name:     "subset of values",
+			expected: models.NewHeaderValues("sid=abc"),
+			fails:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := &yaml_file.Test{
+				ResponseHeaders: map[int]map[string]models.HeaderValue{
+					200: {"Set-Cookie": tt.expected},
+				},
+			}
+			test.HeadersComparisonParams = tt.params
+
+			result := &models.Result{
+				ResponseStatusCode: 200,
+				ResponseHeaders: map[string][]string{
+					"Set-Cookie": {"sid=abc", "lang=en"},
+				},
+			}
+
+			errs, err := NewChecker().Check(test, result)
+
+			assert.NoError(t, err)
+			if tt.fails {
+				assert.Len(t, errs, 1)
+			} else {
+				assert.Empty(t, errs)
+			}
+		})
+	}
+}
+
+func TestCheckDisallowExtraHeaders(t *testing.T) {
+	test := &yaml_file.Test{
+		ResponseHeaders: map[int]map[string]models.HeaderValue{
+			200: {
+				"content-type": models.NewHeaderValue("application/json"),
+				"Date":         models.NewHeaderValue("$matchRegexp(.+)"),
+			},
+		},
+	}
+	test.HeadersComparisonParams = models.HeadersComparisonParams{DisallowExtraHeaders: true}
+
+	result := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseHeaders: map[string][]string{
+			"Content-Type": {"application/json"},
+			"Date":         {"Mon, 19 Oct 2026 10:00:00 GMT"},
+			"X-Debug":      {"1"},
+			"Server":       {"nginx"},
+		},
+	}
+
+	errs, err := NewChecker().Check(test, result)
+
+	assert.NoError(t, err)
+	assert.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], "response includes unexpected header Server")
+	assert.EqualError(t, errs[1], "response includes unexpected header X-Debug")
+}
@@ -0,0 +1,206 @@
+package response_jwt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/lamoda/gonkey/body_decoder"
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/jwt"
+	"github.com/lamoda/gonkey/models"
+)
+
+type ResponseJWTChecker struct {
+	now func() time.Time
+}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseJWTChecker{now: time.Now}
+}
+
+func (c *ResponseJWTChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	assertions, ok := t.GetJWTAssertions(result.ResponseStatusCode)
+	if !ok || len(assertions) == 0 {
+		return nil, nil
+	}
+
+	var errs []error
+	for _, assertion := range assertions {
+		checkErrs, err := c.checkAssertion(assertion, result)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid jwt assertion for test %s (status %d): %w",
+				t.GetName(),
+				result.ResponseStatusCode,
+				err,
+			)
+		}
+		errs = append(errs, checkErrs...)
+	}
+
+	return errs, nil
+}
+
+func (c *ResponseJWTChecker) checkAssertion(assertion models.JWTAssertion, result *models.Result) ([]error, error) {
+	location := tokenLocation(assertion)
+
+	raw, err := extractToken(assertion, result)
+	if err != nil {
+		return []error{models.NewJWTError(location, err)}, nil
+	}
+
+	token, err := jwt.Parse(raw)
+	if err != nil {
+		return []error{models.NewJWTError(location, err)}, nil
+	}
+
+	var errs []error
+	if assertion.Verify != nil {
+		verifyErr, err := verify(token, assertion.Verify)
+		if err != nil {
+			return nil, err
+		}
+		if verifyErr != nil {
+			errs = append(errs, models.NewJWTError(location, verifyErr))
+		}
+	}
+
+	// header and claims are compared as one document, so mismatch paths start with $.header or $.claims
+	expected := map[string]interface{}{}
+	if assertion.TokenHeader != nil {
+		expected["header"] = body_decoder.Normalize(assertion.TokenHeader)
+	}
+	if assertion.Claims != nil {
+		expected["claims"] = body_decoder.Normalize(assertion.Claims)
+	}
+	actual := map[string]interface{}{"header": token.Header, "claims": token.Claims}
+	for _, err := range compare.Compare(expected, actual, compare.Params{}) {
+		errs = append(errs, models.NewJWTError(location, err))
+	}
+
+	timeErrs, err := c.checkTimes(assertion, token)
+	if err != nil {
+		return nil, err
+	}
+	for _, err := range timeErrs {
+		errs = append(errs, models.NewJWTError(location, err))
+	}
+
+	return errs, nil
+}
+
+// checkTimes checks exp and iat claims relative to current time
+func (c *ResponseJWTChecker) checkTimes(assertion models.JWTAssertion, token *jwt.Token) ([]error, error) {
+	now := c.now()
+
+	var errs []error
+	if assertion.ExpiresWithin != "" {
+		within, err := time.ParseDuration(assertion.ExpiresWithin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiresWithin: %w", err)
+		}
+		exp, err := token.Time("exp")
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case !exp.After(now):
+			errs = append(errs, fmt.Errorf("token expired at %s", exp.UTC().Format(time.RFC3339)))
+		case exp.After(now.Add(within)):
+			errs = append(errs, fmt.Errorf("token expires at %s, more than %s from now", exp.UTC().Format(time.RFC3339), within))
+		}
+	}
+
+	if assertion.IssuedWithin != "" {
+		within, err := time.ParseDuration(assertion.IssuedWithin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid issuedWithin: %w", err)
+		}
+		iat, err := token.Time("iat")
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case iat.Before(now.Add(-within)) || iat.After(now.Add(within)):
+			errs = append(errs, fmt.Errorf("token issued at %s, not within %s from now", iat.UTC().Format(time.RFC3339), within))
+		}
+	}
+
+	return errs, nil
+}
+
+func tokenLocation(assertion models.JWTAssertion) string {
+	switch {
+	case assertion.Header != "":
+		return "header " + assertion.Header
+	case assertion.Path != "":
+		return "body " + assertion.Path
+	default:
+		return "body"
+	}
+}
+
+func extractToken(assertion models.JWTAssertion, result *models.Result) (string, error) {
+	if assertion.Header != "" {
+		value := http.Header(result.ResponseHeaders).Get(assertion.Header)
+		if value == "" {
+			return "", errors.New("response has no such header")
+		}
+		if len(value) > 7 && strings.EqualFold(value[:7], "Bearer ") {
+			value = value[7:]
+		}
+
+		return value, nil
+	}
+
+	if assertion.Path == "" {
+		return result.ResponseBody, nil
+	}
+
+	value := gjson.Get(result.ResponseBody, assertion.Path)
+	if !value.Exists() {
+		return "", errors.New("path does not exist in response body")
+	}
+	if value.Type != gjson.String {
+		return "", fmt.Errorf("token must be a string, got %s", value.Raw)
+	}
+
+	return value.String(), nil
+}
+
+// verify checks signature of the token, errors reading keys are returned as the second value
+func verify(token *jwt.Token, verification *models.JWTVerification) (error, error) {
+	switch {
+	case verification.Secret != "":
+		return token.Verify([]byte(verification.Secret)), nil
+	case verification.KeyFile != "":
+		data, err := os.ReadFile(verification.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwt.ParsePEMKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %w", verification.KeyFile, err)
+		}
+
+		return token.Verify(key), nil
+	case verification.JWKSFile != "":
+		data, err := os.ReadFile(verification.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		set, err := jwt.ParseJWKS(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key set file %s: %w", verification.JWKSFile, err)
+		}
+
+		return set.Verify(token), nil
+	default:
+		return nil, errors.New("verify requires secret, keyFile or jwksFile")
+	}
+}
@@ -0,0 +1,200 @@
+package response_jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+var now = time.Unix(1700000000, 0)
+
+func segment(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func hs256(t *testing.T, claims map[string]interface{}, secret string) string {
+	input := segment(t, map[string]interface{}{"alg": "HS256", "typ": "JWT"}) + "." + segment(t, claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(input))
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func es256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	input := segment(t, map[string]interface{}{"alg": "ES256", "kid": kid}) + "." + segment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func check(t *testing.T, result *models.Result, assertions ...models.JWTAssertion) ([]error, error) {
+	test := &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{
+		JWTAssertions: map[int][]models.JWTAssertion{200: assertions},
+	}}
+	checker := &ResponseJWTChecker{now: func() time.Time { return now }}
+	result.ResponseStatusCode = 200
+
+	return checker.Check(test, result)
+}
+
+func TestCheckPasses(t *testing.T) {
+	token := hs256(t, map[string]interface{}{
+		"sub":   "42",
+		"roles": []string{"admin"},
+		"iat":   now.Add(-time.Minute).Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}, "secret")
+	result := &models.Result{
+		ResponseBody:    fmt.Sprintf(`{"auth": {"access_token": %q}}`, token),
+		ResponseHeaders: map[string][]string{"Authorization": {"Bearer " + token}},
+	}
+
+	errs, err := check(t, result,
+		models.JWTAssertion{
+			Path:          "auth.access_token",
+			TokenHeader:   map[string]interface{}{"alg": "HS256"},
+			Claims:        map[string]interface{}{"sub": "42", "roles": []interface{}{"$matchRegexp(^adm)"}},
+			Verify:        &models.JWTVerification{Secret: "secret"},
+			ExpiresWithin: "1h",
+			IssuedWithin:  "5m",
+		},
+		models.JWTAssertion{Header: "authorization", Claims: map[string]interface{}{"iat": int(now.Add(-time.Minute).Unix())}},
+	)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestCheckFails(t *testing.T) {
+	token := hs256(t, map[string]interface{}{
+		"sub": "42",
+		"iat": now.Add(-time.Hour).Unix(),
+		"exp": now.Add(2 * time.Hour).Unix(),
+	}, "secret")
+
+	errs, err := check(t, &models.Result{ResponseBody: token}, models.JWTAssertion{
+		TokenHeader:   map[string]interface{}{"alg": "RS256"},
+		Claims:        map[string]interface{}{"sub": "43"},
+		Verify:        &models.JWTVerification{Secret: "other"},
+		ExpiresWithin: "1h",
+		IssuedWithin:  "5m",
+	})
+	require.NoError(t, err)
+	require.Len(t, errs, 5)
+
+	assert.Equal(t, "jwt body: token signature is invalid", errs[0].Error())
+	var messages []string
+	for _, err := range errs[1:3] {
+		messages = append(messages, err.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		"jwt body: at path $.header.alg values do not match:\n     expected: RS256\n       actual: HS256",
+		"jwt body: at path $.claims.sub values do not match:\n     expected: 43\n       actual: 42",
+	}, messages)
+	assert.Equal(t, "jwt body: token expires at 2023-11-15T00:13:20Z, more than 1h0m0s from now", errs[3].Error())
+	assert.Equal(t, "jwt body: token issued at 2023-11-14T21:13:20Z, not within 5m0s from now", errs[4].Error())
+	assert.Equal(t, "body", errs[0].(*models.CheckError).GetIdentifier())
+}
+
+func TestCheckExpired(t *testing.T) {
+	token := hs256(t, map[string]interface{}{"exp": now.Add(-time.Second).Unix()}, "secret")
+
+	errs, err := check(t, &models.Result{ResponseBody: token}, models.JWTAssertion{ExpiresWithin: "1h", IssuedWithin: "1h"})
+	require.NoError(t, err)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "jwt body: token expired at 2023-11-14T22:13:19Z", errs[0].Error())
+	assert.Equal(t, "jwt body: token has no iat claim", errs[1].Error())
+}
+
+func TestCheckTokenNotFound(t *testing.T) {
+	errs, err := check(t, &models.Result{ResponseBody: `{"token": 1}`},
+		models.JWTAssertion{Path: "access_token"},
+		models.JWTAssertion{Path: "token"},
+		models.JWTAssertion{Header: "Authorization"},
+		models.JWTAssertion{},
+	)
+	require.NoError(t, err)
+	require.Len(t, errs, 4)
+	assert.Equal(t, "jwt body access_token: path does not exist in response body", errs[0].Error())
+	assert.Equal(t, "jwt body token: token must be a string, got 1", errs[1].Error())
+	assert.Equal(t, "jwt header Authorization: response has no such header", errs[2].Error())
+	assert.Equal(t, "jwt body: token must consist of three dot separated parts", errs[3].Error())
+}
+
+func TestCheckVerifiesWithKeyFiles(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	b64 := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	jwksFile := filepath.Join(dir, "jwks.json")
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "EC", "kid": "other", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "EC", "kid": "main", "crv": "P-256", "x": %q, "y": %q}
+	]}`, b64(other.X), b64(other.Y), b64(key.X), b64(key.Y))
+	require.NoError(t, os.WriteFile(jwksFile, []byte(jwks), 0o600))
+
+	result := &models.Result{ResponseBody: es256(t, key, "main", map[string]interface{}{"sub": "42"})}
+	errs, err := check(t, result,
+		models.JWTAssertion{Verify: &models.JWTVerification{KeyFile: keyFile}},
+		models.JWTAssertion{Verify: &models.JWTVerification{JWKSFile: jwksFile}},
+	)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	result = &models.Result{ResponseBody: es256(t, other, "main", map[string]interface{}{"sub": "42"})}
+	errs, err = check(t, result,
+		models.JWTAssertion{Verify: &models.JWTVerification{KeyFile: keyFile}},
+		models.JWTAssertion{Verify: &models.JWTVerification{JWKSFile: jwksFile}},
+	)
+	require.NoError(t, err)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "jwt body: token signature is invalid", errs[0].Error())
+	assert.Equal(t, "jwt body: token signature is invalid", errs[1].Error())
+}
+
+func TestCheckErrors(t *testing.T) {
+	result := &models.Result{ResponseBody: hs256(t, map[string]interface{}{}, "secret")}
+
+	_, err := check(t, result, models.JWTAssertion{Verify: &models.JWTVerification{KeyFile: "missing.pem"}})
+	assert.Error(t, err)
+
+	_, err = check(t, result, models.JWTAssertion{Verify: &models.JWTVerification{}})
+	assert.EqualError(t, err, "invalid jwt assertion for test  (status 200): verify requires secret, keyFile or jwksFile")
+
+	_, err = check(t, result, models.JWTAssertion{ExpiresWithin: "soon"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package response_metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/metrics"
+	"github.com/lamoda/gonkey/models"
+)
+
+// tolerance of float comparison, values of counters incremented by fractions may be imprecise
+const tolerance = 1e-9
+
+type ResponseMetricsChecker struct{}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseMetricsChecker{}
+}
+
+func (c *ResponseMetricsChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	check := t.GetMetricsCheck()
+	if check == nil {
+		return nil, nil
+	}
+
+	var errs []error
+	for _, key := range sortedKeys(check.Delta) {
+		selector, err := metrics.ParseSelector(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics check for test %s: %w", t.GetName(), err)
+		}
+
+		before, _ := result.MetricsBefore.Sum(selector)
+		after, ok := result.MetricsAfter.Sum(selector)
+		switch {
+		case !ok:
+			errs = append(errs, models.NewMetricsError(key, "not found after the request"))
+		case !equal(after-before, check.Delta[key]):
+			errs = append(errs, models.NewMetricsError(
+				key,
+				"expected change %s, got %s (before %s, after %s)",
+				formatDelta(check.Delta[key]),
+				formatDelta(after-before),
+				format(before),
+				format(after),
+			))
+		}
+	}
+
+	for _, key := range sortedKeys(check.Value) {
+		selector, err := metrics.ParseSelector(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics check for test %s: %w", t.GetName(), err)
+		}
+
+		after, ok := result.MetricsAfter.Sum(selector)
+		switch {
+		case !ok:
+			errs = append(errs, models.NewMetricsError(key, "not found after the request"))
+		case !equal(after, check.Value[key]):
+			errs = append(errs, models.NewMetricsError(key, "expected value %s, got %s", format(check.Value[key]), format(after)))
+		}
+	}
+
+	return errs, nil
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func equal(actual, expected float64) bool {
+	return math.Abs(actual-expected) <= tolerance
+}
+
+func format(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func formatDelta(value float64) string {
+	if value >= 0 {
+		return "+" + format(value)
+	}
+
+	return format(value)
+}
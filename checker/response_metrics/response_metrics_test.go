@@ -0,0 +1,86 @@
+package response_metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/metrics"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func parse(t *testing.T, text string) metrics.Samples {
+	samples, err := metrics.Parse(strings.NewReader(text))
+	require.NoError(t, err)
+
+	return samples
+}
+
+func newTest(check *models.MetricsCheck) *yaml_file.Test {
+	return &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{MetricsCheck: check}}
+}
+
+func TestCheckPasses(t *testing.T) {
+	test := newTest(&models.MetricsCheck{
+		Delta: map[string]float64{
+			`orders_created_total{status="ok"}`: 1,
+			`orders_created_total`:              1,
+			`stock_items{sku="a"}`:              -2,
+			`payments_total`:                    1,
+		},
+		Value: map[string]float64{`stock_items{sku="a"}`: 8, `amount_sum`: 0.3},
+	})
+	result := &models.Result{
+		MetricsBefore: parse(t, `
+orders_created_total{status="ok",region="eu"} 3
+orders_created_total{status="failed",region="eu"} 1
+stock_items{sku="a"} 10
+amount_sum 0.1
+`),
+		MetricsAfter: parse(t, `
+orders_created_total{status="ok",region="eu"} 3
+orders_created_total{status="ok",region="us"} 1
+orders_created_total{status="failed",region="eu"} 1
+stock_items{sku="a"} 8
+payments_total 1
+amount_sum 0.30000000000000004
+`),
+	}
+
+	errs, err := NewChecker().Check(test, result)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestCheckFails(t *testing.T) {
+	test := newTest(&models.MetricsCheck{
+		Delta: map[string]float64{`orders_created_total{status="ok"}`: 1, `missing_total`: 1},
+		Value: map[string]float64{`stock_items`: 5},
+	})
+	result := &models.Result{
+		MetricsBefore: parse(t, "orders_created_total{status=\"ok\"} 3\nstock_items 10\n"),
+		MetricsAfter:  parse(t, "orders_created_total{status=\"ok\"} 3\nstock_items 10\n"),
+	}
+
+	errs, err := NewChecker().Check(test, result)
+	require.NoError(t, err)
+	require.Len(t, errs, 3)
+	assert.Equal(t, "metric missing_total: not found after the request", errs[0].Error())
+	assert.Equal(t, `metric orders_created_total{status="ok"}: expected change +1, got +0 (before 3, after 3)`, errs[1].Error())
+	assert.Equal(t, "metric stock_items: expected value 5, got 10", errs[2].Error())
+
+	var checkErr *models.CheckError
+	require.ErrorAs(t, errs[1], &checkErr)
+	assert.Equal(t, models.ErrorCategoryMetrics, checkErr.GetCategory())
+	assert.Equal(t, `orders_created_total{status="ok"}`, checkErr.GetIdentifier())
+}
+
+func TestCheckInvalidSelector(t *testing.T) {
+	test := newTest(&models.MetricsCheck{Delta: map[string]float64{`orders{status=ok}`: 1}})
+
+	_, err := NewChecker().Check(test, &models.Result{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package response_xpath
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/xmlparsing"
+)
+
+type ResponseXPathChecker struct{}
+
+func NewChecker() checker.CheckerInterface {
+	return &ResponseXPathChecker{}
+}
+
+func (c *ResponseXPathChecker) Check(t models.TestInterface, result *models.Result) ([]error, error) {
+	assertions, ok := t.GetXPathAssertions(result.ResponseStatusCode)
+	if !ok || len(assertions) == 0 {
+		return nil, nil
+	}
+
+	doc, err := xmlparsing.ParseDocument(result.ResponseBody)
+	if err != nil {
+		return []error{models.NewBodyErrorWithCause(err, "could not parse response as XML")}, nil
+	}
+
+	var errs []error
+	for _, assertion := range assertions {
+		checkErrs, err := checkAssertion(doc, assertion, t.GetXMLNamespaces())
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid xpath assertion for test %s (status %d): %w",
+				t.GetName(),
+				result.ResponseStatusCode,
+				err,
+			)
+		}
+		errs = append(errs, checkErrs...)
+	}
+
+	return errs, nil
+}
+
+func checkAssertion(doc *xmlparsing.Document, assertion models.XPathAssertion, namespaces map[string]string) ([]error, error) {
+	res, err := doc.Query(assertion.Path, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	shouldExist := assertion.Exists == nil || *assertion.Exists
+	switch {
+	case !shouldExist && res.Exists():
+		return []error{models.NewXPathError(assertion.Path, errors.New("expected to select no nodes"))}, nil
+	case !shouldExist:
+		return nil, nil
+	case !res.Exists():
+		return []error{models.NewXPathError(assertion.Path, errors.New("does not select any node"))}, nil
+	case assertion.Value == nil:
+		return nil, nil
+	}
+
+	expected, actual := res.Comparable(assertion.Value)
+
+	var errs []error
+	for _, err := range compare.Compare(expected, actual, compare.Params{}) {
+		errs = append(errs, models.NewXPathError(assertion.Path, err))
+	}
+
+	return errs, nil
+}
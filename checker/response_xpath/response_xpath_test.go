@@ -0,0 +1,82 @@
+package response_xpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+const body = `
+<env:Envelope xmlns:env="http://schemas.xmlsoap.org/soap/envelope/">
+	<env:Body>
+		<m:Order xmlns:m="urn:shop" id="42">
+			<m:Item>apple</m:Item>
+			<m:Item>pear</m:Item>
+		</m:Order>
+	</env:Body>
+</env:Envelope>`
+
+func newTest(assertions ...models.XPathAssertion) *yaml_file.Test {
+	return &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{
+		XPathAssertions: map[int][]models.XPathAssertion{200: assertions},
+		XMLNamespaces: map[string]string{
+			"soap": "http://schemas.xmlsoap.org/soap/envelope/",
+			"shop": "urn:shop",
+		},
+	}}
+}
+
+func exists(v bool) *bool {
+	return &v
+}
+
+func TestCheckPasses(t *testing.T) {
+	test := newTest(
+		models.XPathAssertion{Path: "/soap:Envelope/soap:Body/shop:Order/@id", Value: 42},
+		models.XPathAssertion{Path: "//shop:Item", Value: []interface{}{"apple", "$matchRegexp(^p)"}},
+		models.XPathAssertion{Path: "count(//shop:Item)", Value: "$matchRegexp(^[0-9]+$)"},
+		models.XPathAssertion{Path: "//shop:Order"},
+		models.XPathAssertion{Path: "//soap:Fault", Exists: exists(false)},
+	)
+
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: body})
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestCheckFails(t *testing.T) {
+	test := newTest(
+		models.XPathAssertion{Path: "//shop:Order/@id", Value: 43},
+		models.XPathAssertion{Path: "//shop:Item", Value: []interface{}{"apple"}},
+		models.XPathAssertion{Path: "//shop:Discount"},
+		models.XPathAssertion{Path: "//shop:Item", Exists: exists(false)},
+	)
+
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: body})
+	require.NoError(t, err)
+	require.Len(t, errs, 4)
+
+	assert.Equal(t, "xpath //shop:Order/@id: at path $ values do not match:\n     expected: 43\n       actual: 42", errs[0].Error())
+	assert.Equal(t, "//shop:Item", errs[1].(*models.CheckError).GetIdentifier())
+	assert.Equal(t, "xpath //shop:Discount: does not select any node", errs[2].Error())
+	assert.Equal(t, "xpath //shop:Item: expected to select no nodes", errs[3].Error())
+}
+
+func TestCheckErrors(t *testing.T) {
+	test := newTest(models.XPathAssertion{Path: "//unknown:Item"})
+
+	_, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: body})
+	assert.Error(t, err)
+
+	errs, err := NewChecker().Check(test, &models.Result{ResponseStatusCode: 200, ResponseBody: `{"a": 1}`})
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+
+	errs, err = NewChecker().Check(test, &models.Result{ResponseStatusCode: 500, ResponseBody: `{"a": 1}`})
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,137 @@
+package compare
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// captureExprRx matches $capture(name) and $capture(name, <matcher>) expressions
+var captureExprRx = regexp.MustCompile(`(?s)^\$capture\(\s*(\w+)\s*(?:,\s*(.*?))?\s*\)$`)
+
+type capturedValue struct {
+	path  string
+	value interface{}
+}
+
+// captures collects values matched by $capture expressions during comparison
+type captures struct {
+	values map[string]capturedValue
+	errors []error
+}
+
+func newCaptures() *captures {
+	return &captures{values: make(map[string]capturedValue)}
+}
+
+func (c *captures) add(path, name string, value interface{}) {
+	if prev, ok := c.values[name]; ok && !reflect.DeepEqual(prev.value, value) {
+		c.errors = append(c.errors, makeError(path, MismatchCaptureConflict, prev.value, value))
+
+		return
+	}
+
+	c.values[name] = capturedValue{path: path, value: value}
+}
+
+func (c *captures) result() map[string]interface{} {
+	res := make(map[string]interface{}, len(c.values))
+	for name, captured := range c.values {
+		res[name] = captured.value
+	}
+
+	return res
+}
+
+// CompareWithCaptures compares values the same way Compare does and returns values of actual value
+// captured by $capture(name) and $capture(name, <matcher>) expressions.
+// Captured values are returned only when there are no mismatches.
+func CompareWithCaptures(expected, actual interface{}, params Params) (map[string]interface{}, []error) {
+	if err := params.compile(); err != nil {
+		return nil, []error{err}
+	}
+
+	params.captures = newCaptures()
+	errs := compareBranch("$", expected, actual, &params)
+	errs = append(errs, params.captures.errors...)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+
+	return params.captures.result(), nil
+}
+
+// captureExpr returns name of variable and matcher (nil if not given) of $capture expression
+func captureExpr(expected interface{}) (name string, matcher interface{}, ok bool) {
+	val, isString := expected.(string)
+	if !isString {
+		return "", nil, false
+	}
+
+	matches := captureExprRx.FindStringSubmatch(val)
+	if matches == nil {
+		return "", nil, false
+	}
+	if matches[2] == "" {
+		return matches[1], nil, true
+	}
+
+	// matcher is either a JSON value ("abc", 42, {"a": 1}) or a string (e.g. $matchRegexp(...))
+	if err := json.Unmarshal([]byte(matches[2]), &matcher); err != nil {
+		matcher = matches[2]
+	}
+
+	return matches[1], matcher, true
+}
+
+func compareCapture(path, name string, matcher, actual interface{}, params *Params) []error {
+	if matcher != nil {
+		if errs := compareBranch(path, matcher, actual, params); len(errs) != 0 {
+			return errs
+		}
+	}
+
+	if params.captures != nil {
+		params.captures.add(path, name, actual)
+	}
+
+	return nil
+}
+
+// captureValues compares matching values once again to capture values of $capture expressions
+// (nothing is captured while looking for a matching pair)
+func captureValues(path string, expected, actual interface{}, params *Params) {
+	if params.captures != nil && containsCaptures(expected) {
+		compareBranch(path, expected, actual, params)
+	}
+}
+
+// containsCaptures reports whether expected value has $capture expressions
+func containsCaptures(expected interface{}) bool {
+	switch v := expected.(type) {
+	case string:
+		return strings.HasPrefix(v, "$capture(") && captureExprRx.MatchString(v)
+	case nil:
+		return false
+	}
+
+	ref := reflect.ValueOf(expected)
+	switch ref.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < ref.Len(); i++ {
+			if containsCaptures(ref.Index(i).Interface()) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := ref.MapRange()
+		for iter.Next() {
+			if containsCaptures(iter.Value().Interface()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package compare
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareWithCaptures(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+		params   Params
+		captures map[string]interface{}
+	}{
+		{
+			name:     "any value",
+			expected: `{"id": "$capture(orderId)", "status": "new"}`,
+			actual:   `{"id": 42, "status": "new"}`,
+			captures: map[string]interface{}{"orderId": float64(42)},
+		},
+		{
+			name:     "value matching regex",
+			expected: `{"token": "$capture(token, $matchRegexp(^[a-f0-9]{8}$))"}`,
+			actual:   `{"token": "deadbeef"}`,
+			captures: map[string]interface{}{"token": "deadbeef"},
+		},
+		{
+			name:     "value equal to JSON literal",
+			expected: `{"a": "$capture(a, {\"b\": 1})", "c": "$capture(c, \"x\")"}`,
+			actual:   `{"a": {"b": 1}, "c": "x"}`,
+			captures: map[string]interface{}{"a": map[string]interface{}{"b": float64(1)}, "c": "x"},
+		},
+		{
+			name:     "element of unordered array matched by other fields",
+			expected: `{"items": [{"sku": "B", "id": "$capture(bId)"}, {"sku": "A", "id": "$capture(aId)"}]}`,
+			actual:   `{"items": [{"sku": "A", "id": 1}, {"sku": "B", "id": 2}]}`,
+			params:   Params{IgnoreArraysOrdering: true},
+			captures: map[string]interface{}{"aId": float64(1), "bId": float64(2)},
+		},
+		{
+			name:     "element of array with contains matching",
+			expected: `{"items": "$contains([{\"sku\": \"B\", \"id\": \"$capture(bId)\"}])"}`,
+			actual:   `{"items": [{"sku": "A", "id": 1}, {"sku": "B", "id": 2}]}`,
+			captures: map[string]interface{}{"bId": float64(2)},
+		},
+		{
+			name:     "same value captured twice",
+			expected: `[{"id": "$capture(id)"}, {"id": "$capture(id)"}]`,
+			actual:   `[{"id": 1}, {"id": 1}]`,
+			captures: map[string]interface{}{"id": float64(1)},
+		},
+		{
+			name:     "no captures",
+			expected: `{"a": 1}`,
+			actual:   `{"a": 1}`,
+			captures: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captures, errs := CompareWithCaptures(unmarshalJSON(t, tt.expected), unmarshalJSON(t, tt.actual), tt.params)
+			require.Empty(t, errs)
+			assert.Equal(t, tt.captures, captures)
+		})
+	}
+}
+
+func TestCompareWithCapturesMismatch(t *testing.T) {
+	captures, errs := CompareWithCaptures(
+		unmarshalJSON(t, `{"id": "$capture(id, $matchRegexp(^\\d+$))", "status": "new"}`),
+		unmarshalJSON(t, `{"id": "abc", "status": "new"}`),
+		Params{},
+	)
+	assert.Nil(t, captures)
+	require.Len(t, errs, 1)
+
+	var mismatch *MismatchError
+	require.True(t, errors.As(errs[0], &mismatch))
+	assert.Equal(t, "$.id", mismatch.Path)
+	assert.Equal(t, MismatchRegex, mismatch.Kind)
+
+	// nothing is captured when other parts do not match
+	captures, errs = CompareWithCaptures(
+		unmarshalJSON(t, `{"id": "$capture(id)", "status": "new"}`),
+		unmarshalJSON(t, `{"id": 1, "status": "done"}`),
+		Params{},
+	)
+	assert.Nil(t, captures)
+	assert.Len(t, errs, 1)
+}
+
+func TestCompareWithCapturesConflict(t *testing.T) {
+	captures, errs := CompareWithCaptures(
+		unmarshalJSON(t, `["$capture(id)", "$capture(id)"]`),
+		unmarshalJSON(t, `[1, 2]`),
+		Params{},
+	)
+	assert.Nil(t, captures)
+	require.Len(t, errs, 1)
+	assert.Equal(t, &MismatchError{
+		Path:     "$[1]",
+		Kind:     MismatchCaptureConflict,
+		Expected: float64(1),
+		Actual:   float64(2),
+	}, errs[0])
+}
+
+func TestCompareCaptureMatchesWithoutCapturing(t *testing.T) {
+	errs := Compare(
+		unmarshalJSON(t, `{"id": "$capture(id, $matchRegexp(^\\d+$))"}`),
+		unmarshalJSON(t, `{"id": "abc"}`),
+		Params{},
+	)
+	assert.Len(t, errs, 1)
+
+	errs = Compare(
+		unmarshalJSON(t, `{"id": "$capture(id)", "items": [{"id": "$capture(id)"}]}`),
+		unmarshalJSON(t, `{"id": 1, "items": [{"id": 2}]}`),
+		Params{},
+	)
+	assert.Empty(t, errs)
+}
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
-
-	"github.com/fatih/color"
 )
 
 type Params struct {
@@ -13,7 +11,125 @@ type Params struct {
 	IgnoreArraysOrdering bool `json:"ignoreArraysOrdering" yaml:"ignoreArraysOrdering"`
 	DisallowExtraFields  bool `json:"disallowExtraFields" yaml:"disallowExtraFields"`
 	IgnoreDbOrdering     bool `json:"IgnoreDbOrdering" yaml:"ignoreDbOrdering"`
-	failFast             bool // End compare operation after first error
+	// ArrayMatch defines how expected arrays are matched with actual ones, see ArrayMatch constants
+	ArrayMatch ArrayMatch `json:"arrayMatch" yaml:"arrayMatch"`
+	// IgnorePaths lists paths (wildcards are allowed, see pathPattern) excluded from comparison
+	IgnorePaths []string `json:"ignorePaths" yaml:"ignorePaths"`
+	// Overrides changes comparison params for particular subtrees
+	Overrides []Override `json:"overrides" yaml:"overrides"`
+	failFast  bool       // End compare operation after first error
+
+	ignoredPatterns  []*pathPattern
+	overridePatterns []*pathPattern
+	captures         *captures // nil when values are not captured
+	// regexes are compiled expressions of $matchRegexp, they are cached for one comparison only,
+	// as expressions with substituted variables are rarely the same in different tests
+	regexes map[string]*regexp.Regexp
+}
+
+// Override sets comparison params for all nodes matching Path and their children.
+// Unset params are inherited from the parent.
+type Override struct {
+	Path   string         `json:"path" yaml:"path"`
+	Params OverrideParams `json:"params" yaml:"params"`
+}
+
+type OverrideParams struct {
+	IgnoreValues         *bool       `json:"ignoreValues" yaml:"ignoreValues"`
+	IgnoreArraysOrdering *bool       `json:"ignoreArraysOrdering" yaml:"ignoreArraysOrdering"`
+	DisallowExtraFields  *bool       `json:"disallowExtraFields" yaml:"disallowExtraFields"`
+	ArrayMatch           *ArrayMatch `json:"arrayMatch" yaml:"arrayMatch"`
+}
+
+// Validate checks that all paths in params are valid
+func (p *Params) Validate() error {
+	return p.compile()
+}
+
+func (p *Params) compile() error {
+	if err := p.ArrayMatch.validate(); err != nil {
+		return err
+	}
+
+	p.regexes = make(map[string]*regexp.Regexp)
+
+	p.ignoredPatterns = make([]*pathPattern, 0, len(p.IgnorePaths))
+	for _, raw := range p.IgnorePaths {
+		pattern, err := compilePathPattern(raw)
+		if err != nil {
+			return fmt.Errorf("invalid ignore path: %w", err)
+		}
+		p.ignoredPatterns = append(p.ignoredPatterns, pattern)
+	}
+
+	p.overridePatterns = make([]*pathPattern, 0, len(p.Overrides))
+	for _, o := range p.Overrides {
+		pattern, err := compilePathPattern(o.Path)
+		if err != nil {
+			return fmt.Errorf("invalid override path: %w", err)
+		}
+		if o.Params.ArrayMatch != nil {
+			if err := o.Params.ArrayMatch.validate(); err != nil {
+				return fmt.Errorf("invalid override for path %s: %w", o.Path, err)
+			}
+		}
+		p.overridePatterns = append(p.overridePatterns, pattern)
+	}
+
+	return nil
+}
+
+func (p *Params) isIgnored(path string) bool {
+	for _, pattern := range p.ignoredPatterns {
+		if pattern.Match(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forPath returns params with overrides matching path applied
+func (p *Params) forPath(path string) *Params {
+	res := p
+	for i, pattern := range p.overridePatterns {
+		if !pattern.Match(path) {
+			continue
+		}
+
+		if res == p {
+			cp := *p
+			res = &cp
+		}
+		res.apply(p.Overrides[i].Params)
+	}
+
+	return res
+}
+
+// trial returns params for comparisons which only check whether values match
+// (e.g. looking for a pair of array element), they stop on first error and capture nothing
+func (p *Params) trial() *Params {
+	res := *p
+	res.failFast = true
+	res.captures = nil
+
+	return &res
+}
+
+func (p *Params) apply(o OverrideParams) {
+	if o.IgnoreValues != nil {
+		p.IgnoreValues = *o.IgnoreValues
+	}
+	if o.IgnoreArraysOrdering != nil {
+		p.IgnoreArraysOrdering = *o.IgnoreArraysOrdering
+	}
+	if o.DisallowExtraFields != nil {
+		p.DisallowExtraFields = *o.DisallowExtraFields
+	}
+	if o.ArrayMatch != nil {
+		p.ArrayMatch = *o.ArrayMatch
+	}
 }
 
 type leafsMatchType int
@@ -30,56 +146,73 @@ const (
 
 var regexExprRx = regexp.MustCompile(`^\$matchRegexp\((.+)\)$`)
 
+// IsMatcher reports whether expected value is a matcher expression ($matchRegexp(...), $contains(...), etc.)
+func IsMatcher(expected interface{}) bool {
+	val, ok := expected.(string)
+
+	return ok && matcherExprRx.MatchString(val)
+}
+
 // Compare compares values as plain text
 // It can be compared several ways:
 //   - Pure values: should be equal
 //   - Regex: try to compile 'expected' as regex and match 'actual' with it
 //     It activates on following syntax: $matchRegexp(%EXPECTED_VALUE%)
+//   - Contains: 'actual' array should contain all elements of JSON array given in expression
+//     It activates on following syntax: $contains([%ELEMENTS%])
+//   - Capture: 'actual' value should match optional matcher, it is returned by CompareWithCaptures
+//     It activates on following syntax: $capture(%NAME%) or $capture(%NAME%, %MATCHER%)
+//   - Embedded document: 'actual' string is parsed and compared with given document using the same params
+//     It activates on following syntax: $matchJSON(%JSON%) or $matchXML(%XML%)
+//   - JWT claims: 'actual' string is decoded as JSON Web Token and its claims are compared with given JSON
+//     It activates on following syntax: $matchJWT(%JSON%)
+//
+// Paths listed in params.IgnorePaths are skipped, params.Overrides are applied to matching subtrees.
 func Compare(expected, actual interface{}, params Params) []error {
+	if err := params.compile(); err != nil {
+		return []error{err}
+	}
+
 	return compareBranch("$", expected, actual, &params)
 }
 
 func compareBranch(path string, expected, actual interface{}, params *Params) []error {
+	if params.isIgnored(path) {
+		return nil
+	}
+	params = params.forPath(path)
+
+	if expr, ok := containsExpr(expected); ok {
+		return compareContains(path, expr, actual, params)
+	}
+
+	if name, matcher, ok := captureExpr(expected); ok {
+		return compareCapture(path, name, matcher, actual, params)
+	}
+
+	if format, document, ok := embeddedExpr(expected); ok {
+		return compareEmbedded(path, format, document, actual, params)
+	}
+
 	expectedType := getType(expected)
 	actualType := getType(actual)
 	var errors []error
 
 	// compare types
 	if leafMatchType(expected) != regex && expectedType != actualType {
-		errors = append(errors, makeError(path, "types do not match", expectedType, actualType))
+		errors = append(errors, makeError(path, MismatchTypes, expectedType, actualType))
 
 		return errors
 	}
 
 	// compare scalars
 	if isScalarType(actualType) && !params.IgnoreValues {
-		return compareLeafs(path, expected, actual)
+		return compareLeafs(path, expected, actual, params)
 	}
 
 	// compare arrays
 	if actualType == arrayType {
-		expectedArray := convertToArray(expected)
-		actualArray := convertToArray(actual)
-
-		if len(expectedArray) != len(actualArray) {
-			errors = append(errors, makeError(path, "array lengths do not match", len(expectedArray), len(actualArray)))
-
-			return errors
-		}
-
-		if params.IgnoreArraysOrdering {
-			expectedArray, actualArray = getUnmatchedArrays(expectedArray, actualArray, params)
-		}
-
-		// iterate over children
-		for i, item := range expectedArray {
-			subPath := fmt.Sprintf("%s[%d]", path, i)
-			res := compareBranch(subPath, item, actualArray[i], params)
-			errors = append(errors, res...)
-			if params.failFast && len(errors) != 0 {
-				return errors
-			}
-		}
+		return compareArrays(path, convertToArray(expected), convertToArray(actual), params)
 	}
 
 	// compare maps
@@ -87,16 +220,25 @@ func compareBranch(path string, expected, actual interface{}, params *Params) []
 		expectedRef := reflect.ValueOf(expected)
 		actualRef := reflect.ValueOf(actual)
 
-		if params.DisallowExtraFields && expectedRef.Len() != actualRef.Len() {
-			errors = append(errors, makeError(path, "map lengths do not match", expectedRef.Len(), actualRef.Len()))
+		if params.DisallowExtraFields {
+			expectedLen := countComparedKeys(path, expectedRef, params)
+			actualLen := countComparedKeys(path, actualRef, params)
+			if expectedLen != actualLen {
+				errors = append(errors, makeError(path, MismatchMapLength, expectedLen, actualLen))
 
-			return errors
+				return errors
+			}
 		}
 
 		for _, key := range expectedRef.MapKeys() {
+			subPath := fmt.Sprintf("%s.%s", path, key.String())
+			if params.isIgnored(subPath) {
+				continue
+			}
+
 			// check keys presence
 			if ok := actualRef.MapIndex(key); !ok.IsValid() {
-				errors = append(errors, makeError(path, "key is missing", key.String(), "<missing>"))
+				errors = append(errors, makeError(path, MismatchKeyMissing, key.String(), "<missing>"))
 				if params.failFast {
 					return errors
 				}
@@ -105,7 +247,6 @@ func compareBranch(path string, expected, actual interface{}, params *Params) []
 			}
 
 			// check values
-			subPath := fmt.Sprintf("%s.%s", path, key.String())
 			res := compareBranch(
 				subPath,
 				expectedRef.MapIndex(key).Interface(),
@@ -122,6 +263,22 @@ func compareBranch(path string, expected, actual interface{}, params *Params) []
 	return errors
 }
 
+// countComparedKeys returns number of map keys which are not ignored
+func countComparedKeys(path string, m reflect.Value, params *Params) int {
+	if len(params.ignoredPatterns) == 0 {
+		return m.Len()
+	}
+
+	count := 0
+	for _, key := range m.MapKeys() {
+		if !params.isIgnored(fmt.Sprintf("%s.%s", path, key.String())) {
+			count++
+		}
+	}
+
+	return count
+}
+
 func getType(value interface{}) string {
 	if value == nil {
 		return "nil"
@@ -142,7 +299,7 @@ func isScalarType(t string) bool {
 	return !(t == "array" || t == "map")
 }
 
-func compareLeafs(path string, expected, actual interface{}) []error {
+func compareLeafs(path string, expected, actual interface{}, params *Params) []error {
 	var errors []error
 
 	switch leafMatchType(expected) {
@@ -150,7 +307,7 @@ func compareLeafs(path string, expected, actual interface{}) []error {
 		errors = append(errors, comparePure(path, expected, actual)...)
 
 	case regex:
-		errors = append(errors, compareRegex(path, expected, actual)...)
+		errors = append(errors, compareRegex(path, expected, actual, params)...)
 
 	default:
 		panic("unknown compare type")
@@ -161,31 +318,31 @@ func compareLeafs(path string, expected, actual interface{}) []error {
 
 func comparePure(path string, expected, actual interface{}) (errors []error) {
 	if expected != actual {
-		errors = append(errors, makeError(path, "values do not match", expected, actual))
+		errors = append(errors, makeError(path, MismatchValues, expected, actual))
 	}
 
 	return errors
 }
 
-func compareRegex(path string, expected, actual interface{}) (errors []error) {
+func compareRegex(path string, expected, actual interface{}, params *Params) (errors []error) {
 	regexExpr, ok := expected.(string)
 	if !ok {
-		errors = append(errors, makeError(path, "type mismatch", "string", reflect.TypeOf(expected)))
+		errors = append(errors, makeError(path, MismatchRegexType, "string", reflect.TypeOf(expected)))
 
 		return errors
 	}
 
 	value := fmt.Sprintf("%v", actual)
 
-	rx, err := regexp.Compile(retrieveRegexStr(regexExpr))
+	rx, err := params.compileRegex(retrieveRegexStr(regexExpr))
 	if err != nil {
-		errors = append(errors, makeError(path, "can not compile regex", nil, "error"))
+		errors = append(errors, makeError(path, MismatchRegexCompile, nil, "error"))
 
 		return errors
 	}
 
 	if !rx.MatchString(value) {
-		errors = append(errors, makeError(path, "value does not match regex", expected, actual))
+		errors = append(errors, makeError(path, MismatchRegex, expected, actual))
 
 		return errors
 	}
@@ -193,6 +350,24 @@ func compareRegex(path string, expected, actual interface{}) (errors []error) {
 	return nil
 }
 
+// compileRegex compiles regex once per comparison, the same expression is usually matched many times
+// (e.g. with every element of unordered array)
+func (p *Params) compileRegex(expr string) (*regexp.Regexp, error) {
+	if rx, ok := p.regexes[expr]; ok {
+		return rx, nil
+	}
+
+	rx, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	if p.regexes != nil {
+		p.regexes[expr] = rx
+	}
+
+	return rx, nil
+}
+
 func retrieveRegexStr(expr string) string {
 	if matches := regexExprRx.FindStringSubmatch(expr); matches != nil {
 		return matches[1]
@@ -214,16 +389,6 @@ func leafMatchType(expected interface{}) leafsMatchType {
 	return pure
 }
 
-func makeError(path, msg string, expected, actual interface{}) error {
-	return fmt.Errorf(
-		"at path %s %s:\n     expected: %s\n       actual: %s",
-		color.CyanString(path),
-		msg,
-		color.GreenString("%v", expected),
-		color.RedString("%v", actual),
-	)
-}
-
 func convertToArray(array interface{}) []interface{} {
 	ref := reflect.ValueOf(array)
 
@@ -234,36 +399,3 @@ func convertToArray(array interface{}) []interface{} {
 
 	return interfaceSlice
 }
-
-// For every elem in "expected" try to find elem in "actual". Returns arrays without matching.
-func getUnmatchedArrays(expected, actual []interface{}, params *Params) (expectedUnmatched, actualUnmatched []interface{}) {
-	expectedError := make([]interface{}, 0)
-
-	failfastParams := *params
-	failfastParams.failFast = true
-
-	for _, expectedElem := range expected {
-		found := false
-		for i, actualElem := range actual {
-			if len(compareBranch("", expectedElem, actualElem, &failfastParams)) == 0 {
-				// expectedElem match actualElem
-				found = true
-				// remove actualElem from  actual
-				if len(actual) != 1 {
-					actual[i] = actual[len(actual)-1]
-				}
-				actual = actual[:len(actual)-1]
-
-				break
-			}
-		}
-		if !found {
-			expectedError = append(expectedError, expectedElem)
-			if params.failFast {
-				return expectedError, actual[0:1]
-			}
-		}
-	}
-
-	return expectedError, actual
-}
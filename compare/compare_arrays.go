@@ -0,0 +1,151 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// ArrayMatch defines how expected array is matched with actual one
+type ArrayMatch string
+
+const (
+	// ArrayMatchExact requires arrays to have the same length and matching elements (default)
+	ArrayMatchExact ArrayMatch = "exact"
+	// ArrayMatchContains requires actual array to contain all expected elements, extra elements are ignored
+	ArrayMatchContains ArrayMatch = "contains"
+	// ArrayMatchPrefix requires actual array to start with expected elements, extra elements are ignored
+	ArrayMatchPrefix ArrayMatch = "prefix"
+)
+
+var containsExprRx = regexp.MustCompile(`(?s)^\$contains\((.+)\)$`)
+
+func (m ArrayMatch) validate() error {
+	switch m {
+	case "", ArrayMatchExact, ArrayMatchContains, ArrayMatchPrefix:
+		return nil
+	default:
+		return fmt.Errorf(
+			"unknown arrayMatch %q (expecting %s, %s or %s)",
+			m, ArrayMatchExact, ArrayMatchContains, ArrayMatchPrefix,
+		)
+	}
+}
+
+// compareArrays compares arrays according to params.ArrayMatch.
+// With IgnoreArraysOrdering elements are matched in any order, otherwise their order is preserved.
+func compareArrays(path string, expected, actual []interface{}, params *Params) []error {
+	switch params.ArrayMatch {
+	case ArrayMatchContains:
+		return compareArraysContains(path, expected, actual, params)
+	case ArrayMatchPrefix:
+		if len(actual) < len(expected) {
+			return []error{makeError(path, MismatchArrayTooShort, len(expected), len(actual))}
+		}
+
+		return compareArraysExact(path, expected, actual[:len(expected)], params)
+	default:
+		if len(expected) != len(actual) {
+			return []error{makeError(path, MismatchArrayLength, len(expected), len(actual))}
+		}
+
+		return compareArraysExact(path, expected, actual, params)
+	}
+}
+
+func compareArraysExact(path string, expectedArray, actualArray []interface{}, params *Params) []error {
+	var errors []error
+
+	if params.IgnoreArraysOrdering {
+		expectedArray, actualArray = getUnmatchedArrays(path, expectedArray, actualArray, params)
+	}
+
+	// iterate over children
+	for i, item := range expectedArray {
+		subPath := fmt.Sprintf("%s[%d]", path, i)
+		res := compareBranch(subPath, item, actualArray[i], params)
+		errors = append(errors, res...)
+		if params.failFast && len(errors) != 0 {
+			return errors
+		}
+	}
+
+	return errors
+}
+
+func compareArraysContains(path string, expected, actual []interface{}, params *Params) []error {
+	if len(actual) < len(expected) {
+		return []error{makeError(path, MismatchArrayTooShort, len(expected), len(actual))}
+	}
+
+	if params.IgnoreArraysOrdering {
+		var errors []error
+		unmatched, _ := getUnmatchedArrays(path, expected, actual, params)
+		for _, item := range unmatched {
+			errors = append(errors, makeError(path, MismatchArrayElementMissing, item, "<missing>"))
+			if params.failFast {
+				return errors
+			}
+		}
+
+		return errors
+	}
+
+	trialParams := params.trial()
+
+	// greedy matching of the earliest suitable element keeps the order and
+	// leaves as many actual elements as possible for the rest of expected ones
+	var errors []error
+	cursor := 0
+	for i, item := range expected {
+		subPath := fmt.Sprintf("%s[%d]", path, i)
+		found := false
+		for j := cursor; j < len(actual); j++ {
+			if len(compareBranch(subPath, item, actual[j], trialParams)) == 0 {
+				found = true
+				cursor = j + 1
+				captureValues(subPath, item, actual[j], params)
+
+				break
+			}
+		}
+
+		if !found {
+			errors = append(errors, makeError(subPath, MismatchArrayElementMissing, item, "<missing>"))
+			if params.failFast {
+				return errors
+			}
+		}
+	}
+
+	return errors
+}
+
+// containsExpr returns expression of $contains(...) matcher
+func containsExpr(expected interface{}) (string, bool) {
+	val, ok := expected.(string)
+	if !ok {
+		return "", false
+	}
+
+	matches := containsExprRx.FindStringSubmatch(val)
+	if matches == nil {
+		return "", false
+	}
+
+	return matches[1], true
+}
+
+func compareContains(path, expr string, actual interface{}, params *Params) []error {
+	var expected []interface{}
+	if err := json.Unmarshal([]byte(expr), &expected); err != nil {
+		return []error{makeError(path, MismatchInvalidExpression, "$contains([...]) with JSON array", err)}
+	}
+
+	if actualType := getType(actual); actualType != arrayType {
+		return []error{makeError(path, MismatchTypes, arrayType, actualType)}
+	}
+
+	// $contains affects only current array, nested ones are compared according to params
+	return compareArraysContains(path, expected, convertToArray(actual), params)
+}
@@ -0,0 +1,152 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareArrayMatchContains(t *testing.T) {
+	tests := []struct {
+		name           string
+		expected       string
+		actual         string
+		ignoreOrdering bool
+		wantErrs       []string
+	}{
+		{
+			name:     "subset in order",
+			expected: `[1, 3]`,
+			actual:   `[1, 2, 3, 4]`,
+		},
+		{
+			name:     "equal arrays",
+			expected: `[1, 2]`,
+			actual:   `[1, 2]`,
+		},
+		{
+			name:     "wrong order",
+			expected: `[3, 1]`,
+			actual:   `[1, 2, 3]`,
+			wantErrs: []string{makeErrorString("$[1]", "array does not contain expected element", 1, "<missing>")},
+		},
+		{
+			name:           "wrong order ignoring ordering",
+			expected:       `[3, 1]`,
+			actual:         `[1, 2, 3]`,
+			ignoreOrdering: true,
+		},
+		{
+			name:     "missing element",
+			expected: `[1, 5]`,
+			actual:   `[1, 2, 3]`,
+			wantErrs: []string{makeErrorString("$[1]", "array does not contain expected element", 5, "<missing>")},
+		},
+		{
+			name:           "missing element ignoring ordering",
+			expected:       `[5, 1]`,
+			actual:         `[1, 2, 3]`,
+			ignoreOrdering: true,
+			wantErrs:       []string{makeErrorString("$", "array does not contain expected element", 5, "<missing>")},
+		},
+		{
+			name:     "duplicates are counted",
+			expected: `[1, 1]`,
+			actual:   `[1, 2]`,
+			wantErrs: []string{makeErrorString("$[1]", "array does not contain expected element", 1, "<missing>")},
+		},
+		{
+			name:     "shorter actual array",
+			expected: `[1, 2, 3]`,
+			actual:   `[1, 2]`,
+			wantErrs: []string{makeErrorString("$", "array is shorter than expected", 3, 2)},
+		},
+		{
+			name:     "objects with regex",
+			expected: `[{"id": "$matchRegexp(^b)"}]`,
+			actual:   `[{"id": "a1", "x": 1}, {"id": "b2", "x": 2}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Compare(unmarshalJSON(t, tt.expected), unmarshalJSON(t, tt.actual), Params{
+				ArrayMatch:           ArrayMatchContains,
+				IgnoreArraysOrdering: tt.ignoreOrdering,
+			})
+			require.Len(t, errs, len(tt.wantErrs), "%v", errs)
+			for i, err := range errs {
+				assert.Equal(t, tt.wantErrs[i], err.Error())
+			}
+		})
+	}
+}
+
+func TestCompareArrayMatchPrefix(t *testing.T) {
+	expected := unmarshalJSON(t, `[1, 2]`)
+
+	errs := Compare(expected, unmarshalJSON(t, `[1, 2, 3]`), Params{ArrayMatch: ArrayMatchPrefix})
+	assert.Empty(t, errs)
+
+	errs = Compare(expected, unmarshalJSON(t, `[2, 1, 3]`), Params{ArrayMatch: ArrayMatchPrefix})
+	assert.Len(t, errs, 2)
+
+	errs = Compare(expected, unmarshalJSON(t, `[2, 1, 3]`), Params{
+		ArrayMatch:           ArrayMatchPrefix,
+		IgnoreArraysOrdering: true,
+	})
+	assert.Empty(t, errs)
+
+	errs = Compare(expected, unmarshalJSON(t, `[1, 3, 2]`), Params{
+		ArrayMatch:           ArrayMatchPrefix,
+		IgnoreArraysOrdering: true,
+	})
+	assert.Len(t, errs, 1)
+
+	errs = Compare(expected, unmarshalJSON(t, `[1]`), Params{ArrayMatch: ArrayMatchPrefix})
+	require.Len(t, errs, 1)
+	assert.Equal(t, makeErrorString("$", "array is shorter than expected", 2, 1), errs[0].Error())
+}
+
+func TestCompareArrayMatchOverride(t *testing.T) {
+	contains := ArrayMatchContains
+	expected := unmarshalJSON(t, `{"search": [1], "exact": [1]}`)
+	actual := unmarshalJSON(t, `{"search": [1, 2], "exact": [1, 2]}`)
+
+	errs := Compare(expected, actual, Params{
+		Overrides: []Override{{Path: "$.search", Params: OverrideParams{ArrayMatch: &contains}}},
+	})
+	require.Len(t, errs, 1)
+	assert.Equal(t, makeErrorString("$.exact", "array lengths do not match", 1, 2), errs[0].Error())
+}
+
+func TestCompareContainsExpression(t *testing.T) {
+	expected := unmarshalJSON(t, `{"items": "$contains([{\"id\": 2, \"tags\": [\"a\"]}])"}`)
+
+	errs := Compare(expected, unmarshalJSON(t, `{"items": [{"id": 1}, {"id": 2, "tags": ["a"]}]}`), Params{})
+	assert.Empty(t, errs)
+
+	// nested arrays are compared according to params
+	errs = Compare(expected, unmarshalJSON(t, `{"items": [{"id": 2, "tags": ["a", "b"]}]}`), Params{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, makeErrorString("$.items[0]", "array does not contain expected element",
+		map[string]interface{}{"id": float64(2), "tags": []interface{}{"a"}}, "<missing>"), errs[0].Error())
+
+	errs = Compare(expected, unmarshalJSON(t, `{"items": {"id": 2}}`), Params{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, makeErrorString("$.items", "types do not match", "array", "map"), errs[0].Error())
+
+	errs = Compare("$contains([1,)", []interface{}{1}, Params{})
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "can not parse expression")
+
+	errs = Compare(`$contains(["b", "a"])`, []interface{}{"a", "c", "b"}, Params{IgnoreArraysOrdering: true})
+	assert.Empty(t, errs)
+}
+
+func TestCompareInvalidArrayMatch(t *testing.T) {
+	errs := Compare(1, 1, Params{ArrayMatch: "subset"})
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "unknown arrayMatch")
+}
@@ -0,0 +1,129 @@
+package compare
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func unmarshalJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+
+	return v
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestCompareIgnorePaths(t *testing.T) {
+	expected := unmarshalJSON(t, `{
+		"meta": {"requestId": "abc", "page": 1},
+		"items": [{"id": 1, "updatedAt": "2020-01-01"}, {"id": 2, "updatedAt": "2020-01-01"}]
+	}`)
+	actual := unmarshalJSON(t, `{
+		"meta": {"requestId": "xyz", "page": 1},
+		"items": [{"id": 1, "updatedAt": "2024-05-05"}, {"id": 2, "updatedAt": "2024-05-06"}]
+	}`)
+
+	errs := Compare(expected, actual, Params{})
+	assert.Len(t, errs, 3)
+
+	errs = Compare(expected, actual, Params{
+		IgnorePaths: []string{"$.meta.requestId", "$.items[*].updatedAt"},
+	})
+	assert.Empty(t, errs)
+}
+
+func TestCompareIgnorePathsMissingKey(t *testing.T) {
+	expected := unmarshalJSON(t, `{"id": 1, "requestId": "abc"}`)
+	actual := unmarshalJSON(t, `{"id": 1}`)
+
+	errs := Compare(expected, actual, Params{IgnorePaths: []string{"$.requestId"}})
+	assert.Empty(t, errs)
+}
+
+func TestCompareIgnorePathsWithDisallowExtraFields(t *testing.T) {
+	expected := unmarshalJSON(t, `{"id": 1}`)
+	actual := unmarshalJSON(t, `{"id": 1, "requestId": "abc"}`)
+
+	errs := Compare(expected, actual, Params{DisallowExtraFields: true})
+	require.Len(t, errs, 1)
+	assert.Equal(t, makeErrorString("$", "map lengths do not match", 1, 2), errs[0].Error())
+
+	errs = Compare(expected, actual, Params{
+		DisallowExtraFields: true,
+		IgnorePaths:         []string{"$..requestId"},
+	})
+	assert.Empty(t, errs)
+}
+
+func TestCompareIgnorePathsWithIgnoreArraysOrdering(t *testing.T) {
+	expected := unmarshalJSON(t, `[{"id": 1, "ts": 1}, {"id": 2, "ts": 2}]`)
+	actual := unmarshalJSON(t, `[{"id": 2, "ts": 20}, {"id": 1, "ts": 10}]`)
+
+	errs := Compare(expected, actual, Params{
+		IgnoreArraysOrdering: true,
+		IgnorePaths:          []string{"$[*].ts"},
+	})
+	assert.Empty(t, errs)
+}
+
+func TestCompareOverrides(t *testing.T) {
+	expected := unmarshalJSON(t, `{
+		"tags": ["a", "b"],
+		"ordered": ["a", "b"],
+		"strict": {"id": 1},
+		"loose": {"id": 1}
+	}`)
+	actual := unmarshalJSON(t, `{
+		"tags": ["b", "a"],
+		"ordered": ["b", "a"],
+		"strict": {"id": 1, "extra": true},
+		"loose": {"id": 1, "extra": true}
+	}`)
+
+	errs := Compare(expected, actual, Params{
+		Overrides: []Override{
+			{Path: "$.tags", Params: OverrideParams{IgnoreArraysOrdering: boolPtr(true)}},
+			{Path: "$.strict", Params: OverrideParams{DisallowExtraFields: boolPtr(true)}},
+		},
+	})
+	require.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error()+errs[1].Error()+errs[2].Error(), "map lengths do not match")
+
+	errs = Compare(expected, actual, Params{
+		DisallowExtraFields: true,
+		Overrides: []Override{
+			{Path: "$", Params: OverrideParams{DisallowExtraFields: boolPtr(false)}},
+			{Path: "$.*", Params: OverrideParams{IgnoreArraysOrdering: boolPtr(true)}},
+		},
+	})
+	assert.Empty(t, errs)
+}
+
+func TestCompareOverridesIgnoreValues(t *testing.T) {
+	expected := unmarshalJSON(t, `{"id": 1, "stats": {"count": 10, "sum": 100}}`)
+	actual := unmarshalJSON(t, `{"id": 1, "stats": {"count": 11, "sum": 101}}`)
+
+	errs := Compare(expected, actual, Params{
+		Overrides: []Override{
+			{Path: "$.stats", Params: OverrideParams{IgnoreValues: boolPtr(true)}},
+		},
+	})
+	assert.Empty(t, errs)
+}
+
+func TestCompareInvalidPaths(t *testing.T) {
+	errs := Compare(1, 1, Params{IgnorePaths: []string{"items[0"}})
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "invalid ignore path")
+
+	params := Params{Overrides: []Override{{Path: "$.a["}}}
+	assert.Error(t, params.Validate())
+}
@@ -5,20 +5,28 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
 )
 
 func makeErrorString(path, msg string, expected, actual interface{}) string {
 	return fmt.Sprintf(
-		"at path %s %s:\n     expected: %s\n       actual: %s",
-		color.CyanString(path),
+		"at path %s %s:\n     expected: %v\n       actual: %v",
+		path,
 		msg,
-		color.GreenString("%v", expected),
-		color.RedString("%v", actual),
+		expected,
+		actual,
 	)
 }
 
+func TestIsMatcher(t *testing.T) {
+	assert.True(t, IsMatcher("$matchRegexp(^\\d+$)"))
+	assert.True(t, IsMatcher("$contains([1, 2])"))
+	assert.True(t, IsMatcher("$capture(id)"))
+	assert.False(t, IsMatcher("$notAMatcher"))
+	assert.False(t, IsMatcher("plain"))
+	assert.False(t, IsMatcher(42))
+}
+
 func TestCompareNils(t *testing.T) {
 	errors := Compare(nil, nil, Params{})
 	if len(errors) != 0 {
@@ -119,6 +127,23 @@ func TestCheckRegexCantCompile(t *testing.T) {
 	}
 }
 
+func TestCompileRegexCachesPerComparison(t *testing.T) {
+	params := Params{}
+	assert.NoError(t, params.compile())
+
+	rx, err := params.compileRegex("^[0-9]+$")
+	assert.NoError(t, err)
+	cached, _ := params.compileRegex("^[0-9]+$")
+	assert.Same(t, rx, cached)
+	failFastParams := params
+	failFastParams.failFast = true
+	assert.Same(t, rx, failFastParams.regexes["^[0-9]+$"], "copies of params share the cache")
+
+	// the next comparison starts with an empty cache
+	assert.NoError(t, params.compile())
+	assert.Empty(t, params.regexes)
+}
+
 func TestCompareEqualArrays(t *testing.T) {
 	array1 := []string{"1", "2"}
 	array2 := []string{"1", "2"}
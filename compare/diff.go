@@ -0,0 +1,451 @@
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DiffFormat defines how Diff is rendered
+type DiffFormat string
+
+const (
+	// DiffUnified renders diff in unified format (as `diff -u` does)
+	DiffUnified DiffFormat = "unified"
+	// DiffSideBySide renders expected and actual lines in two columns
+	DiffSideBySide DiffFormat = "side-by-side"
+)
+
+// DiffLineKind tells what a line of rendered diff is, e.g. to colorize it
+type DiffLineKind int
+
+const (
+	DiffLineContext DiffLineKind = iota
+	DiffLineHeader
+	DiffLineRemoved // line exists only in expected value
+	DiffLineAdded   // line exists only in actual value
+	DiffLineChanged // side-by-side line differing in expected and actual values
+)
+
+const (
+	diffContextLines   = 3
+	diffMaxColumnWidth = 80
+)
+
+// DiffLine is a line of rendered diff
+type DiffLine struct {
+	Kind DiffLineKind
+	Text string
+}
+
+// ParseDiffFormat converts format name to DiffFormat, empty name means that diff is disabled
+func ParseDiffFormat(name string) (DiffFormat, error) {
+	switch format := DiffFormat(name); format {
+	case "", DiffUnified, DiffSideBySide:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown diff format %q (expecting %s or %s)", name, DiffUnified, DiffSideBySide)
+	}
+}
+
+// Diff holds pretty-printed expected and actual values prepared for line-by-line comparison.
+//
+// Expected value is printed as is (with matchers), while parts of actual value which satisfy
+// expected ones are aligned with them: matched matchers are shown on both sides, ignored paths
+// and allowed extra fields or array elements are omitted and unordered arrays are reordered
+// the way their elements were matched. So only real mismatches differ.
+type Diff struct {
+	Expected string
+	Actual   string
+}
+
+// NewDiff prepares diff of values compared by Compare with the same params
+func NewDiff(expected, actual interface{}, params Params) (*Diff, error) {
+	if err := params.compile(); err != nil {
+		return nil, err
+	}
+
+	expected, actual = alignBranch("$", expected, actual, &params)
+
+	return &Diff{
+		Expected: prettyPrint(expected),
+		Actual:   prettyPrint(actual),
+	}, nil
+}
+
+// Render returns diff in given format as plain text
+func (d *Diff) Render(format DiffFormat) string {
+	var buf strings.Builder
+	for _, line := range d.Lines(format) {
+		buf.WriteString(line.Text)
+		buf.WriteByte('\n')
+	}
+
+	return buf.String()
+}
+
+// Lines returns lines of diff rendered in given format (unified by default)
+func (d *Diff) Lines(format DiffFormat) []DiffLine {
+	if d.Expected == d.Actual {
+		return nil
+	}
+
+	expected := strings.Split(d.Expected, "\n")
+	actual := strings.Split(d.Actual, "\n")
+	groups := difflib.NewMatcher(expected, actual).GetGroupedOpCodes(diffContextLines)
+
+	if format == DiffSideBySide {
+		return sideBySideLines(expected, actual, groups)
+	}
+
+	return unifiedLines(expected, actual, groups)
+}
+
+func unifiedLines(expected, actual []string, groups [][]difflib.OpCode) []DiffLine {
+	lines := []DiffLine{
+		{Kind: DiffLineHeader, Text: "--- expected"},
+		{Kind: DiffLineHeader, Text: "+++ actual"},
+	}
+
+	for _, group := range groups {
+		first, last := group[0], group[len(group)-1]
+		lines = append(lines, DiffLine{
+			Kind: DiffLineHeader,
+			Text: fmt.Sprintf(
+				"@@ -%s +%s @@",
+				formatUnifiedRange(first.I1, last.I2),
+				formatUnifiedRange(first.J1, last.J2),
+			),
+		})
+
+		for _, op := range group {
+			if op.Tag == 'e' {
+				for _, line := range expected[op.I1:op.I2] {
+					lines = append(lines, DiffLine{Kind: DiffLineContext, Text: " " + line})
+				}
+
+				continue
+			}
+			if op.Tag == 'r' || op.Tag == 'd' {
+				for _, line := range expected[op.I1:op.I2] {
+					lines = append(lines, DiffLine{Kind: DiffLineRemoved, Text: "-" + line})
+				}
+			}
+			if op.Tag == 'r' || op.Tag == 'i' {
+				for _, line := range actual[op.J1:op.J2] {
+					lines = append(lines, DiffLine{Kind: DiffLineAdded, Text: "+" + line})
+				}
+			}
+		}
+	}
+
+	return lines
+}
+
+// formatUnifiedRange formats range of lines [start, stop) the way unified diff does
+func formatUnifiedRange(start, stop int) string {
+	length := stop - start
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, length)
+	}
+}
+
+func sideBySideLines(expected, actual []string, groups [][]difflib.OpCode) []DiffLine {
+	width := len("expected")
+	for _, group := range groups {
+		for _, op := range group {
+			for _, line := range expected[op.I1:op.I2] {
+				if n := utf8.RuneCountInString(line); n > width {
+					width = n
+				}
+			}
+		}
+	}
+	if width > diffMaxColumnWidth {
+		width = diffMaxColumnWidth
+	}
+
+	row := func(kind DiffLineKind, left, marker, right string) DiffLine {
+		text := fmt.Sprintf("%-*s %s %s", width, left, marker, right)
+
+		return DiffLine{Kind: kind, Text: strings.TrimRight(text, " ")}
+	}
+
+	lines := []DiffLine{row(DiffLineHeader, "expected", " ", "actual")}
+	for n, group := range groups {
+		if n > 0 {
+			lines = append(lines, DiffLine{Kind: DiffLineHeader, Text: "..."})
+		}
+
+		for _, op := range group {
+			left, right := expected[op.I1:op.I2], actual[op.J1:op.J2]
+			switch op.Tag {
+			case 'e':
+				for i := range left {
+					lines = append(lines, row(DiffLineContext, left[i], " ", right[i]))
+				}
+			case 'd':
+				for _, line := range left {
+					lines = append(lines, row(DiffLineRemoved, line, "<", ""))
+				}
+			case 'i':
+				for _, line := range right {
+					lines = append(lines, row(DiffLineAdded, "", ">", line))
+				}
+			case 'r':
+				for i := 0; i < len(left) || i < len(right); i++ {
+					switch {
+					case i >= len(right):
+						lines = append(lines, row(DiffLineRemoved, left[i], "<", ""))
+					case i >= len(left):
+						lines = append(lines, row(DiffLineAdded, "", ">", right[i]))
+					default:
+						lines = append(lines, row(DiffLineChanged, left[i], "|", right[i]))
+					}
+				}
+			}
+		}
+	}
+
+	return lines
+}
+
+// alignBranch returns expected and actual values where matching parts of actual value
+// are aligned with expected one (see Diff)
+func alignBranch(path string, expected, actual interface{}, params *Params) (interface{}, interface{}) {
+	if params.isIgnored(path) {
+		return expected, expected
+	}
+	params = params.forPath(path)
+
+	// embedded documents are shown parsed, so differences inside of them are visible
+	if format, document, ok := embeddedExpr(expected); ok {
+		if expectedValue, actualValue, errs := parseEmbedded(path, format, document, actual); errs == nil {
+			return alignBranch(path, expectedValue, actualValue, params)
+		}
+	}
+
+	if val, ok := expected.(string); ok && matcherExprRx.MatchString(val) {
+		if len(compareBranch(path, expected, actual, params.trial())) == 0 {
+			return expected, expected
+		}
+
+		return expected, actual
+	}
+
+	actualType := getType(actual)
+	if getType(expected) != actualType {
+		return expected, actual
+	}
+
+	switch {
+	case actualType == arrayType:
+		return alignArrays(path, convertToArray(expected), convertToArray(actual), params)
+	case actualType == mapType:
+		return alignMaps(path, reflect.ValueOf(expected), reflect.ValueOf(actual), params)
+	case params.IgnoreValues:
+		return expected, expected
+	default:
+		return expected, actual
+	}
+}
+
+func alignMaps(path string, expectedRef, actualRef reflect.Value, params *Params) (interface{}, interface{}) {
+	expected := make(map[string]interface{}, expectedRef.Len())
+	actual := make(map[string]interface{}, actualRef.Len())
+
+	for _, key := range expectedRef.MapKeys() {
+		subPath := fmt.Sprintf("%s.%s", path, key.String())
+		if params.isIgnored(subPath) {
+			continue
+		}
+
+		value := actualRef.MapIndex(key)
+		if !value.IsValid() {
+			expected[key.String()] = expectedRef.MapIndex(key).Interface()
+
+			continue
+		}
+		expected[key.String()], actual[key.String()] = alignBranch(
+			subPath,
+			expectedRef.MapIndex(key).Interface(),
+			value.Interface(),
+			params,
+		)
+	}
+
+	// extra fields are shown only when they cause mismatch
+	if params.DisallowExtraFields {
+		for _, key := range actualRef.MapKeys() {
+			subPath := fmt.Sprintf("%s.%s", path, key.String())
+			if expectedRef.MapIndex(key).IsValid() || params.isIgnored(subPath) {
+				continue
+			}
+			actual[key.String()] = actualRef.MapIndex(key).Interface()
+		}
+	}
+
+	return expected, actual
+}
+
+func alignArrays(path string, expected, actual []interface{}, params *Params) (interface{}, interface{}) {
+	pairs := arrayPairs(path, expected, actual, params)
+
+	alignedExpected := make([]interface{}, len(expected))
+	alignedActual := make([]interface{}, 0, len(actual))
+	paired := make([]bool, len(actual))
+	for i, item := range expected {
+		j := pairs[i]
+		if j == -1 {
+			alignedExpected[i] = item
+
+			continue
+		}
+
+		paired[j] = true
+		var value interface{}
+		alignedExpected[i], value = alignBranch(fmt.Sprintf("%s[%d]", path, i), item, actual[j], params)
+		alignedActual = append(alignedActual, value)
+	}
+
+	// extra elements are shown only when they cause mismatch
+	if params.ArrayMatch == "" || params.ArrayMatch == ArrayMatchExact {
+		for j, item := range actual {
+			if !paired[j] {
+				alignedActual = append(alignedActual, item)
+			}
+		}
+	}
+
+	return alignedExpected, alignedActual
+}
+
+// arrayPairs returns index of actual element paired with every expected element (-1 if none)
+// the same way compareArrays matches them
+func arrayPairs(path string, expected, actual []interface{}, params *Params) []int {
+	if params.ArrayMatch == ArrayMatchContains {
+		if params.IgnoreArraysOrdering {
+			return matchedPairs(path, expected, actual, params)
+		}
+
+		return orderedContainsPairs(path, expected, actual, params)
+	}
+
+	// elements are compared by position, so mismatching ones are paired too
+	// to show differences inside of them
+	compared := actual
+	if params.ArrayMatch == ArrayMatchPrefix && len(actual) > len(expected) {
+		compared = actual[:len(expected)]
+	}
+
+	var pairs []int
+	if params.IgnoreArraysOrdering {
+		pairs = matchedPairs(path, expected, compared, params)
+	} else {
+		pairs = make([]int, len(expected))
+		for i := range pairs {
+			pairs[i] = -1
+		}
+	}
+
+	paired := make([]bool, len(compared))
+	for _, j := range pairs {
+		if j != -1 {
+			paired[j] = true
+		}
+	}
+
+	next := 0
+	for i := range pairs {
+		if pairs[i] != -1 {
+			continue
+		}
+		for next < len(compared) && paired[next] {
+			next++
+		}
+		if next == len(compared) {
+			break
+		}
+		pairs[i] = next
+		paired[next] = true
+	}
+
+	return pairs
+}
+
+func matchedPairs(path string, expected, actual []interface{}, params *Params) []int {
+	m := newArrayMatcher(path, expected, actual, params)
+	m.failFast = false
+	m.match()
+
+	return m.expectedPair
+}
+
+func orderedContainsPairs(path string, expected, actual []interface{}, params *Params) []int {
+	trialParams := params.trial()
+
+	pairs := make([]int, len(expected))
+	cursor := 0
+	for i, item := range expected {
+		pairs[i] = -1
+		subPath := fmt.Sprintf("%s[%d]", path, i)
+		for j := cursor; j < len(actual); j++ {
+			if len(compareBranch(subPath, item, actual[j], trialParams)) == 0 {
+				pairs[i] = j
+				cursor = j + 1
+
+				break
+			}
+		}
+	}
+
+	return pairs
+}
+
+func prettyPrint(value interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(jsonCompatible(value)); err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// jsonCompatible converts maps with non-string keys (e.g. decoded from YAML) to map[string]interface{}
+func jsonCompatible(value interface{}) interface{} {
+	ref := reflect.ValueOf(value)
+	switch {
+	case !ref.IsValid():
+		return value
+	case ref.Kind() == reflect.Map:
+		res := make(map[string]interface{}, ref.Len())
+		iter := ref.MapRange()
+		for iter.Next() {
+			res[fmt.Sprintf("%v", iter.Key().Interface())] = jsonCompatible(iter.Value().Interface())
+		}
+
+		return res
+	case ref.Kind() == reflect.Slice && ref.Type().Elem().Kind() != reflect.Uint8:
+		res := make([]interface{}, ref.Len())
+		for i := range res {
+			res[i] = jsonCompatible(ref.Index(i).Interface())
+		}
+
+		return res
+	default:
+		return value
+	}
+}
@@ -0,0 +1,172 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffUnified(t *testing.T) {
+	expected := unmarshalJSON(t, `{"id": "$matchRegexp(^\\d+$)", "name": "apple", "tags": ["a", "b"]}`)
+	actual := unmarshalJSON(t, `{"id": "42", "name": "pear", "tags": ["a", "b"], "extra": true}`)
+
+	diff, err := NewDiff(expected, actual, Params{})
+	require.NoError(t, err)
+
+	assert.Equal(t, `--- expected
++++ actual
+@@ -1,6 +1,6 @@
+ {
+   "id": "$matchRegexp(^\\d+$)",
+-  "name": "apple",
++  "name": "pear",
+   "tags": [
+     "a",
+     "b"
+`, diff.Render(DiffUnified))
+}
+
+func TestDiffSideBySide(t *testing.T) {
+	diff, err := NewDiff(
+		unmarshalJSON(t, `{"a": 1, "b": [1, 2]}`),
+		unmarshalJSON(t, `{"a": 2, "b": [1]}`),
+		Params{},
+	)
+	require.NoError(t, err)
+
+	lines := diff.Lines(DiffSideBySide)
+	kinds := make([]DiffLineKind, 0, len(lines))
+	for _, line := range lines {
+		kinds = append(kinds, line.Kind)
+	}
+	assert.Equal(t, []DiffLineKind{
+		DiffLineHeader,
+		DiffLineContext,
+		DiffLineChanged,
+		DiffLineContext,
+		DiffLineChanged,
+		DiffLineRemoved,
+		DiffLineContext,
+		DiffLineContext,
+	}, kinds)
+	assert.Equal(t, `expected    actual
+{           {
+  "a": 1, |   "a": 2,
+  "b": [      "b": [
+    1,    |     1
+    2     <
+  ]           ]
+}           }
+`, diff.Render(DiffSideBySide))
+}
+
+func TestDiffNoDifference(t *testing.T) {
+	diff, err := NewDiff(
+		unmarshalJSON(t, `{"a": "$matchRegexp(.+)", "b": [1, 2]}`),
+		unmarshalJSON(t, `{"a": "x", "b": [2, 1], "c": 3}`),
+		Params{IgnoreArraysOrdering: true},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, diff.Expected, diff.Actual)
+	assert.Empty(t, diff.Lines(DiffUnified))
+	assert.Empty(t, diff.Render(DiffSideBySide))
+}
+
+func TestDiffAlignment(t *testing.T) {
+	tests := []struct {
+		name            string
+		expected        string
+		actual          string
+		params          Params
+		alignedActual   string
+		alignedExpected string
+	}{
+		{
+			name:          "unordered array elements follow expected order",
+			expected:      `[{"id": 1}, {"id": 2}, {"id": 3}]`,
+			actual:        `[{"id": 3}, {"id": 4}, {"id": 1}]`,
+			params:        Params{IgnoreArraysOrdering: true},
+			alignedActual: `[{"id": 1}, {"id": 4}, {"id": 3}]`,
+		},
+		{
+			name:          "extra fields are shown when disallowed",
+			expected:      `{"a": 1}`,
+			actual:        `{"a": 1, "b": 2}`,
+			params:        Params{DisallowExtraFields: true},
+			alignedActual: `{"a": 1, "b": 2}`,
+		},
+		{
+			name:            "ignored paths are omitted",
+			expected:        `{"a": 1, "meta": {"id": "x"}}`,
+			actual:          `{"a": 2, "meta": {"id": "y"}}`,
+			params:          Params{IgnorePaths: []string{"$.meta.id"}},
+			alignedExpected: `{"a": 1, "meta": {}}`,
+			alignedActual:   `{"a": 2, "meta": {}}`,
+		},
+		{
+			name:          "values are aligned when ignored",
+			expected:      `{"a": 1, "b": [1]}`,
+			actual:        `{"a": 2, "b": [1, 2]}`,
+			params:        Params{IgnoreValues: true},
+			alignedActual: `{"a": 1, "b": [1, 2]}`,
+		},
+		{
+			name:          "contains drops extra elements",
+			expected:      `[1, 3, 5]`,
+			actual:        `[1, 2, 3, 4]`,
+			params:        Params{ArrayMatch: ArrayMatchContains},
+			alignedActual: `[1, 3]`,
+		},
+		{
+			name:          "prefix drops extra elements",
+			expected:      `[1, 2]`,
+			actual:        `[1, 3, 4]`,
+			params:        Params{ArrayMatch: ArrayMatchPrefix},
+			alignedActual: `[1, 3]`,
+		},
+		{
+			name:          "matched $contains is aligned",
+			expected:      `{"a": "$contains([2])"}`,
+			actual:        `{"a": [1, 2]}`,
+			alignedActual: `{"a": "$contains([2])"}`,
+		},
+		{
+			name:          "unmatched matcher keeps actual value",
+			expected:      `{"a": "$matchRegexp(^x)"}`,
+			actual:        `{"a": "y"}`,
+			alignedActual: `{"a": "y"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := NewDiff(unmarshalJSON(t, tt.expected), unmarshalJSON(t, tt.actual), tt.params)
+			require.NoError(t, err)
+
+			alignedExpected := tt.alignedExpected
+			if alignedExpected == "" {
+				alignedExpected = tt.expected
+			}
+			assert.Equal(t, prettyPrint(unmarshalJSON(t, alignedExpected)), diff.Expected)
+			assert.Equal(t, prettyPrint(unmarshalJSON(t, tt.alignedActual)), diff.Actual)
+		})
+	}
+}
+
+func TestDiffInvalidParams(t *testing.T) {
+	_, err := NewDiff(nil, nil, Params{IgnorePaths: []string{"a"}})
+	assert.Error(t, err)
+}
+
+func TestParseDiffFormat(t *testing.T) {
+	for _, name := range []string{"", "unified", "side-by-side"} {
+		format, err := ParseDiffFormat(name)
+		require.NoError(t, err)
+		assert.Equal(t, DiffFormat(name), format)
+	}
+
+	_, err := ParseDiffFormat("context")
+	assert.EqualError(t, err, `unknown diff format "context" (expecting unified or side-by-side)`)
+}
@@ -0,0 +1,100 @@
+package compare
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/lamoda/gonkey/jwt"
+	"github.com/lamoda/gonkey/xmlparsing"
+)
+
+// embeddedExprRx matches $matchJSON(...), $matchXML(...) and $matchJWT(...) expressions
+var embeddedExprRx = regexp.MustCompile(`(?s)^\$match(JSON|XML|JWT)\((.+)\)$`)
+
+type embeddedFormat string
+
+const (
+	embeddedJSON embeddedFormat = "JSON"
+	embeddedXML  embeddedFormat = "XML"
+	embeddedJWT  embeddedFormat = "JWT"
+)
+
+// embeddedExpr returns format and expected document of $matchJSON/$matchXML/$matchJWT matcher
+func embeddedExpr(expected interface{}) (embeddedFormat, string, bool) {
+	val, ok := expected.(string)
+	if !ok {
+		return "", "", false
+	}
+
+	matches := embeddedExprRx.FindStringSubmatch(val)
+	if matches == nil {
+		return "", "", false
+	}
+
+	return embeddedFormat(matches[1]), matches[2], true
+}
+
+// documentFormat returns format of the expected document, claims of JWT are described with JSON
+func (f embeddedFormat) documentFormat() embeddedFormat {
+	if f == embeddedJWT {
+		return embeddedJSON
+	}
+
+	return f
+}
+
+func (f embeddedFormat) parse(document string) (interface{}, error) {
+	switch f {
+	case embeddedXML:
+		return xmlparsing.Parse(document)
+	case embeddedJWT:
+		token, err := jwt.Parse(document)
+		if err != nil {
+			return nil, err
+		}
+
+		return token.Claims, nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(document), &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// parseEmbedded parses expected document of the matcher and actual string value
+func parseEmbedded(path string, format embeddedFormat, document string, actual interface{}) (
+	expectedValue, actualValue interface{},
+	errs []error,
+) {
+	expectedFormat := format.documentFormat()
+	expectedValue, err := expectedFormat.parse(document)
+	if err != nil {
+		return nil, nil, []error{makeError(path, MismatchInvalidExpression, "$match"+string(format)+"(...) with "+string(expectedFormat), err)}
+	}
+
+	str, ok := actual.(string)
+	if !ok {
+		return nil, nil, []error{makeError(path, MismatchTypes, "string", getType(actual))}
+	}
+
+	actualValue, err = format.parse(str)
+	if err != nil {
+		return nil, nil, []error{makeError(path, MismatchEmbeddedDocument, string(format), err)}
+	}
+
+	return expectedValue, actualValue, nil
+}
+
+// compareEmbedded compares string containing JSON or XML document or claims of JWT with expected one,
+// nested values are compared with the same params and reported with full paths
+func compareEmbedded(path string, format embeddedFormat, document string, actual interface{}, params *Params) []error {
+	expectedValue, actualValue, errs := parseEmbedded(path, format, document, actual)
+	if errs != nil {
+		return errs
+	}
+
+	return compareBranch(path, expectedValue, actualValue, params)
+}
@@ -0,0 +1,139 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareEmbeddedJSON(t *testing.T) {
+	expected := unmarshalJSON(t, `{"payload": "$matchJSON({\"a\": 1, \"b\": [\"$matchRegexp(^x)\"]})"}`)
+
+	errs := Compare(expected, unmarshalJSON(t, `{"payload": "{\"a\": 1, \"b\": [\"xyz\"], \"c\": 2}"}`), Params{})
+	assert.Empty(t, errs)
+
+	errs = Compare(expected, unmarshalJSON(t, `{"payload": "{\"a\": 2, \"b\": [\"y\"]}"}`), Params{})
+	require.Len(t, errs, 2)
+	assert.ElementsMatch(t, []error{
+		&MismatchError{Path: "$.payload.a", Kind: MismatchValues, Expected: float64(1), Actual: float64(2)},
+		&MismatchError{Path: "$.payload.b[0]", Kind: MismatchRegex, Expected: "$matchRegexp(^x)", Actual: "y"},
+	}, errs)
+}
+
+func TestCompareEmbeddedJSONUsesParams(t *testing.T) {
+	expected := unmarshalJSON(t, `{"payload": "$matchJSON({\"items\": [1, 2]})"}`)
+	actual := unmarshalJSON(t, `{"payload": "{\"items\": [2, 1], \"extra\": true}"}`)
+
+	assert.Empty(t, Compare(expected, actual, Params{IgnoreArraysOrdering: true}))
+
+	errs := Compare(expected, actual, Params{IgnoreArraysOrdering: true, DisallowExtraFields: true})
+	require.Len(t, errs, 1)
+	assert.Equal(t, &MismatchError{Path: "$.payload", Kind: MismatchMapLength, Expected: 1, Actual: 2}, errs[0])
+
+	errs = Compare(expected, actual, Params{IgnorePaths: []string{"$.payload.items"}})
+	assert.Empty(t, errs)
+}
+
+func TestCompareEmbeddedXML(t *testing.T) {
+	expected := map[string]interface{}{"payload": `$matchXML(<order id="1"><status>$matchRegexp(^new|paid$)</status></order>)`}
+
+	errs := Compare(expected, map[string]interface{}{"payload": `<order id="1"><status>paid</status></order>`}, Params{})
+	assert.Empty(t, errs)
+
+	errs = Compare(expected, map[string]interface{}{"payload": `<order id="2"><status>paid</status></order>`}, Params{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, "$.payload.order.-attrs.id", errs[0].(*MismatchError).Path)
+}
+
+func TestCompareEmbeddedJWT(t *testing.T) {
+	// {"alg": "HS256"}.{"sub": "42", "roles": ["admin"], "exp": 1700000000} signed with "secret"
+	token := "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiI0MiIsInJvbGVzIjpbImFkbWluIl0sImV4cCI6MTcwMDAwMDAwMH0." +
+		"sZ_L7eHEjJQka8DIwkMzZRRVZ0U9skAKrJyagw0FkKw"
+	expected := map[string]interface{}{"token": `$matchJWT({"sub": "42", "roles": ["$matchRegexp(^adm)"]})`}
+
+	errs := Compare(expected, map[string]interface{}{"token": token}, Params{})
+	assert.Empty(t, errs)
+
+	expected = map[string]interface{}{"token": `$matchJWT({"sub": "43"})`}
+	errs = Compare(expected, map[string]interface{}{"token": token}, Params{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, &MismatchError{Path: "$.token.sub", Kind: MismatchValues, Expected: "43", Actual: "42"}, errs[0])
+}
+
+func TestCompareEmbeddedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+		kind     MismatchKind
+	}{
+		{
+			name:     "actual is not a string",
+			expected: `$matchJSON({"a": 1})`,
+			actual:   map[string]interface{}{"a": float64(1)},
+			kind:     MismatchTypes,
+		},
+		{
+			name:     "actual is not JSON",
+			expected: `$matchJSON({"a": 1})`,
+			actual:   `a=1`,
+			kind:     MismatchEmbeddedDocument,
+		},
+		{
+			name:     "actual is not XML",
+			expected: `$matchXML(<a/>)`,
+			actual:   `{"a": 1}`,
+			kind:     MismatchEmbeddedDocument,
+		},
+		{
+			name:     "actual is not JWT",
+			expected: `$matchJWT({"sub": "42"})`,
+			actual:   `{"sub": "42"}`,
+			kind:     MismatchEmbeddedDocument,
+		},
+		{
+			name:     "invalid expected claims",
+			expected: `$matchJWT(sub=42)`,
+			actual:   `a.b.c`,
+			kind:     MismatchInvalidExpression,
+		},
+		{
+			name:     "invalid expected document",
+			expected: `$matchJSON({a: 1})`,
+			actual:   `{"a": 1}`,
+			kind:     MismatchInvalidExpression,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Compare(tt.expected, tt.actual, Params{})
+			require.Len(t, errs, 1)
+			assert.Equal(t, "$", errs[0].(*MismatchError).Path)
+			assert.Equal(t, tt.kind, errs[0].(*MismatchError).Kind)
+		})
+	}
+}
+
+func TestCompareEmbeddedCaptures(t *testing.T) {
+	captures, errs := CompareWithCaptures(
+		unmarshalJSON(t, `{"payload": "$matchJSON({\"id\": \"$capture(id)\"})"}`),
+		unmarshalJSON(t, `{"payload": "{\"id\": 7}"}`),
+		Params{},
+	)
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"id": float64(7)}, captures)
+}
+
+func TestDiffEmbeddedJSON(t *testing.T) {
+	diff, err := NewDiff(
+		unmarshalJSON(t, `{"payload": "$matchJSON({\"a\": 1})"}`),
+		unmarshalJSON(t, `{"payload": "{\"a\": 2}"}`),
+		Params{},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, prettyPrint(unmarshalJSON(t, `{"payload": {"a": 1}}`)), diff.Expected)
+	assert.Equal(t, prettyPrint(unmarshalJSON(t, `{"payload": {"a": 2}}`)), diff.Actual)
+}
@@ -0,0 +1,74 @@
+package compare
+
+import "fmt"
+
+// MismatchKind identifies the reason of a mismatch
+type MismatchKind string
+
+const (
+	MismatchTypes               MismatchKind = "types_mismatch"
+	MismatchValues              MismatchKind = "values_mismatch"
+	MismatchRegex               MismatchKind = "regex_mismatch"
+	MismatchRegexCompile        MismatchKind = "regex_compile_error"
+	MismatchRegexType           MismatchKind = "regex_type_mismatch"
+	MismatchArrayLength         MismatchKind = "array_length_mismatch"
+	MismatchArrayTooShort       MismatchKind = "array_too_short"
+	MismatchArrayElementMissing MismatchKind = "array_element_missing"
+	MismatchMapLength           MismatchKind = "map_length_mismatch"
+	MismatchKeyMissing          MismatchKind = "key_missing"
+	MismatchInvalidExpression   MismatchKind = "invalid_expression"
+	MismatchCaptureConflict     MismatchKind = "capture_conflict"
+	MismatchEmbeddedDocument    MismatchKind = "embedded_document_error"
+)
+
+var mismatchMessages = map[MismatchKind]string{
+	MismatchTypes:               "types do not match",
+	MismatchValues:              "values do not match",
+	MismatchRegex:               "value does not match regex",
+	MismatchRegexCompile:        "can not compile regex",
+	MismatchRegexType:           "type mismatch",
+	MismatchArrayLength:         "array lengths do not match",
+	MismatchArrayTooShort:       "array is shorter than expected",
+	MismatchArrayElementMissing: "array does not contain expected element",
+	MismatchMapLength:           "map lengths do not match",
+	MismatchKeyMissing:          "key is missing",
+	MismatchInvalidExpression:   "can not parse expression",
+	MismatchCaptureConflict:     "variable is already captured with another value",
+	MismatchEmbeddedDocument:    "can not parse embedded document",
+}
+
+// Message returns human-readable description of the mismatch kind
+func (k MismatchKind) Message() string {
+	if msg, ok := mismatchMessages[k]; ok {
+		return msg
+	}
+
+	return string(k)
+}
+
+// MismatchError describes a single difference between expected and actual values
+type MismatchError struct {
+	Path     string       `json:"path"`
+	Kind     MismatchKind `json:"kind"`
+	Expected interface{}  `json:"expected"`
+	Actual   interface{}  `json:"actual"`
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf(
+		"at path %s %s:\n     expected: %v\n       actual: %v",
+		e.Path,
+		e.Kind.Message(),
+		e.Expected,
+		e.Actual,
+	)
+}
+
+func makeError(path string, kind MismatchKind, expected, actual interface{}) error {
+	return &MismatchError{
+		Path:     path,
+		Kind:     kind,
+		Expected: expected,
+		Actual:   actual,
+	}
+}
@@ -0,0 +1,35 @@
+package compare
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMismatchError(t *testing.T) {
+	errs := Compare(
+		map[string]interface{}{"a": []interface{}{1, "$matchRegexp(^x)"}},
+		map[string]interface{}{"a": []interface{}{2, "y"}},
+		Params{},
+	)
+	require.Len(t, errs, 2)
+
+	var mismatch *MismatchError
+	require.True(t, errors.As(errs[0], &mismatch))
+	assert.Equal(t, &MismatchError{Path: "$.a[0]", Kind: MismatchValues, Expected: 1, Actual: 2}, mismatch)
+
+	require.True(t, errors.As(errs[1], &mismatch))
+	assert.Equal(t, "$.a[1]", mismatch.Path)
+	assert.Equal(t, MismatchRegex, mismatch.Kind)
+	assert.Equal(t, "value does not match regex", mismatch.Kind.Message())
+	assert.Equal(t, "at path $.a[1] value does not match regex:\n     expected: $matchRegexp(^x)\n       actual: y",
+		mismatch.Error())
+
+	data, err := json.Marshal(mismatch)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"path": "$.a[1]", "kind": "regex_mismatch", "expected": "$matchRegexp(^x)", "actual": "y"}`,
+		string(data))
+}
@@ -0,0 +1,133 @@
+package compare
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pathPattern is a compiled path expression in the same notation compareBranch
+// uses to report errors ($.a[0].b). Following wildcards are supported:
+//   - `*` matches any single map key or array index ($.meta.*, $.items.*)
+//   - `[*]` matches any array index ($.items[*].updatedAt)
+//   - `..` matches any number of nested levels ($..updatedAt)
+type pathPattern struct {
+	raw      string
+	segments []string
+}
+
+const (
+	anySegment = "*"
+	anyIndex   = "[*]"
+	anyDepth   = ".."
+)
+
+func compilePathPattern(raw string) (*pathPattern, error) {
+	expr := strings.TrimSpace(raw)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("path %q must start with $", raw)
+	}
+
+	segments, err := splitPath(expr[1:], true)
+	if err != nil {
+		return nil, fmt.Errorf("path %q: %w", raw, err)
+	}
+
+	return &pathPattern{raw: raw, segments: segments}, nil
+}
+
+// Match reports whether concrete path (as produced by compareBranch) matches the pattern.
+func (p *pathPattern) Match(path string) bool {
+	if !strings.HasPrefix(path, "$") {
+		return false
+	}
+
+	segments, err := splitPath(path[1:], false)
+	if err != nil {
+		return false
+	}
+
+	return matchSegments(p.segments, segments)
+}
+
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == anyDepth {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(path) == 0 || !matchSegment(pattern[0], path[0]) {
+		return false
+	}
+
+	return matchSegments(pattern[1:], path[1:])
+}
+
+func matchSegment(pattern, segment string) bool {
+	switch pattern {
+	case anySegment:
+		return true
+	case anyIndex:
+		return isIndexSegment(segment)
+	default:
+		return pattern == segment
+	}
+}
+
+func isIndexSegment(segment string) bool {
+	return strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]")
+}
+
+// splitPath splits path (without leading $) into segments:
+// map keys are kept as is and array indexes are kept with brackets, e.g. ".a[0].b" -> [a [0] b].
+// When wildcards is true, the recursive descent operator ".." is kept as a separate segment.
+func splitPath(path string, wildcards bool) ([]string, error) {
+	var segments []string
+
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			if wildcards && strings.HasPrefix(path, anyDepth) {
+				segments = append(segments, anyDepth)
+				path = path[len(anyDepth):]
+				if path == "" {
+					return nil, fmt.Errorf("%q must be followed by key or index", anyDepth)
+				}
+				if path[0] == '[' {
+					continue
+				}
+			} else {
+				path = path[1:]
+			}
+
+			end := strings.IndexAny(path, ".[")
+			if end == -1 {
+				end = len(path)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key at %q", path)
+			}
+			segments = append(segments, path[:end])
+			path = path[end:]
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed bracket at %q", path)
+			}
+			segments = append(segments, path[:end+1])
+			path = path[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected character at %q", path)
+		}
+	}
+
+	return segments, nil
+}
@@ -0,0 +1,49 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"$", "$", true},
+		{"$.meta.requestId", "$.meta.requestId", true},
+		{"$.meta.requestId", "$.meta", false},
+		{"$.meta.requestId", "$.meta.requestId.x", false},
+		{"$.items[0].id", "$.items[0].id", true},
+		{"$.items[0].id", "$.items[1].id", false},
+		{"$.items[*].updatedAt", "$.items[3].updatedAt", true},
+		{"$.items[*].updatedAt", "$.items.updatedAt", false},
+		{"$.meta.*", "$.meta.requestId", true},
+		{"$.*.id", "$.items.id", true},
+		{"$.*", "$[0]", true},
+		{"$[*]", "$[12]", true},
+		{"$..updatedAt", "$.updatedAt", true},
+		{"$..updatedAt", "$.items[2].updatedAt", true},
+		{"$..updatedAt", "$.items[2].createdAt", false},
+		{"$.items..id", "$.items[0].children[1].id", true},
+		{"$..[*]", "$.a.b[1]", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			p, err := compilePathPattern(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p.Match(tt.path))
+		})
+	}
+}
+
+func TestPathPatternInvalid(t *testing.T) {
+	for _, raw := range []string{"meta.id", "$.items[0", "$.a..", "$x"} {
+		_, err := compilePathPattern(raw)
+		assert.Error(t, err, raw)
+	}
+}
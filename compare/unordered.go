@@ -0,0 +1,325 @@
+package compare
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// matcherExprRx recognizes any matcher expression ($matchRegexp(...), $contains(...), etc.),
+// elements containing matchers can't be matched by fingerprint.
+var matcherExprRx = regexp.MustCompile(`(?s)^\$\w+\(.*\)$`)
+
+// getUnmatchedArrays matches elements of "expected" with elements of "actual" regardless of their order
+// and returns elements left without pair (in their original order).
+//
+// Matching is a maximum bipartite matching, so an element with a matcher never "steals" an actual
+// element needed by another expected element. To keep it fast on large arrays:
+//   - elements without matchers are paired with actual elements having the same fingerprint first;
+//   - with strict params (no extra fields, no ignored paths, etc.) such elements can match only
+//     elements with the same fingerprint, so the rest of actual elements are not even compared;
+//   - other map elements are compared only with elements having the same value of a plain field.
+func getUnmatchedArrays(
+	path string,
+	expected, actual []interface{},
+	params *Params,
+) (expectedUnmatched, actualUnmatched []interface{}) {
+	m := newArrayMatcher(path, expected, actual, params)
+	m.match()
+
+	for i, a := range m.expectedPair {
+		if a == -1 {
+			expectedUnmatched = append(expectedUnmatched, expected[i])
+		} else {
+			captureValues(m.expectedPaths[i], expected[i], actual[a], params)
+		}
+	}
+	for j, e := range m.actualPair {
+		if e == -1 {
+			actualUnmatched = append(actualUnmatched, actual[j])
+		}
+	}
+
+	return expectedUnmatched, actualUnmatched
+}
+
+type arrayMatcher struct {
+	expected []interface{}
+	actual   []interface{}
+	params   *Params
+	strict   bool
+	failFast bool
+
+	expectedPaths        []string
+	expectedFingerprints []uint64
+	hasMatchers          []bool
+	actualBuckets        map[uint64][]int
+
+	// actual elements indexed by values of their map keys (key -> value -> elements)
+	keyIndex map[string]map[string][]int
+
+	expectedPair  []int
+	actualPair    []int
+	allCandidates []int
+	visited       []int
+	visitMark     int
+}
+
+func newArrayMatcher(path string, expected, actual []interface{}, params *Params) *arrayMatcher {
+	m := &arrayMatcher{
+		expected:             expected,
+		actual:               actual,
+		params:               params.trial(),
+		strict:               isStrict(params),
+		failFast:             params.failFast,
+		expectedPaths:        make([]string, len(expected)),
+		expectedFingerprints: make([]uint64, len(expected)),
+		hasMatchers:          make([]bool, len(expected)),
+		actualBuckets:        make(map[uint64][]int, len(actual)),
+		expectedPair:         make([]int, len(expected)),
+		actualPair:           make([]int, len(actual)),
+		keyIndex:             make(map[string]map[string][]int),
+		allCandidates:        make([]int, len(actual)),
+		visited:              make([]int, len(actual)),
+	}
+
+	for i, e := range expected {
+		m.expectedPair[i] = -1
+		m.expectedPaths[i] = fmt.Sprintf("%s[%d]", path, i)
+		m.hasMatchers[i] = containsMatchers(e)
+		if !m.hasMatchers[i] {
+			m.expectedFingerprints[i] = fingerprint(e)
+		}
+	}
+	for j, a := range actual {
+		m.actualPair[j] = -1
+		m.allCandidates[j] = j
+		fp := fingerprint(a)
+		m.actualBuckets[fp] = append(m.actualBuckets[fp], j)
+	}
+
+	return m
+}
+
+// isStrict reports whether matching values are necessarily equal (up to arrays ordering)
+func isStrict(params *Params) bool {
+	return params.DisallowExtraFields &&
+		!params.IgnoreValues &&
+		params.IgnoreArraysOrdering &&
+		(params.ArrayMatch == "" || params.ArrayMatch == ArrayMatchExact) &&
+		len(params.ignoredPatterns) == 0 &&
+		len(params.overridePatterns) == 0
+}
+
+func (m *arrayMatcher) match() {
+	// pair elements having the same fingerprint
+	bucketPos := make(map[uint64]int, len(m.actualBuckets))
+	for i := range m.expected {
+		if m.hasMatchers[i] {
+			continue
+		}
+
+		fp := m.expectedFingerprints[i]
+		bucket := m.actualBuckets[fp]
+		for bucketPos[fp] < len(bucket) {
+			j := bucket[bucketPos[fp]]
+			bucketPos[fp]++
+			if m.actualPair[j] == -1 && m.isEdge(i, j) {
+				m.expectedPair[i] = j
+				m.actualPair[j] = i
+
+				break
+			}
+		}
+	}
+
+	// find augmenting paths for the rest of elements
+	for i := range m.expected {
+		if m.expectedPair[i] != -1 {
+			continue
+		}
+
+		m.visitMark++
+		if !m.augment(i) && m.failFast {
+			return
+		}
+	}
+}
+
+// augment tries to find a pair for expected element i, re-pairing already matched elements if needed
+func (m *arrayMatcher) augment(i int) bool {
+	for _, j := range m.candidates(i) {
+		if m.visited[j] == m.visitMark || !m.isEdge(i, j) {
+			continue
+		}
+		m.visited[j] = m.visitMark
+
+		if m.actualPair[j] == -1 || m.augment(m.actualPair[j]) {
+			m.expectedPair[i] = j
+			m.actualPair[j] = i
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *arrayMatcher) candidates(i int) []int {
+	if m.strict && !m.hasMatchers[i] {
+		return m.actualBuckets[m.expectedFingerprints[i]]
+	}
+
+	if key, value, ok := m.indexableField(i); ok {
+		return m.indexByKey(key)[value]
+	}
+
+	return m.allCandidates
+}
+
+// indexableField returns a top-level field of expected element which must be equal
+// to the same field of a matching actual element
+func (m *arrayMatcher) indexableField(i int) (key, value string, ok bool) {
+	if m.params.IgnoreValues || len(m.params.ignoredPatterns) != 0 || len(m.params.overridePatterns) != 0 {
+		return "", "", false
+	}
+
+	ref := reflect.ValueOf(m.expected[i])
+	if !ref.IsValid() || ref.Kind() != reflect.Map {
+		return "", "", false
+	}
+
+	keys := make([]string, 0, ref.Len())
+	values := make(map[string]interface{}, ref.Len())
+	iter := ref.MapRange()
+	for iter.Next() {
+		k := fmt.Sprint(iter.Key().Interface())
+		keys = append(keys, k)
+		values[k] = iter.Value().Interface()
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := values[k]
+		if isScalarType(getType(v)) && !containsMatchers(v) {
+			return k, canonicalString(v), true
+		}
+	}
+
+	return "", "", false
+}
+
+func (m *arrayMatcher) indexByKey(key string) map[string][]int {
+	if index, ok := m.keyIndex[key]; ok {
+		return index
+	}
+
+	index := make(map[string][]int)
+	for j, a := range m.actual {
+		ref := reflect.ValueOf(a)
+		if !ref.IsValid() || ref.Kind() != reflect.Map {
+			continue
+		}
+
+		iter := ref.MapRange()
+		for iter.Next() {
+			if fmt.Sprint(iter.Key().Interface()) == key {
+				value := canonicalString(iter.Value().Interface())
+				index[value] = append(index[value], j)
+
+				break
+			}
+		}
+	}
+	m.keyIndex[key] = index
+
+	return index
+}
+
+func (m *arrayMatcher) isEdge(i, j int) bool {
+	return len(compareBranch(m.expectedPaths[i], m.expected[i], m.actual[j], m.params)) == 0
+}
+
+func containsMatchers(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return matcherExprRx.MatchString(v)
+	case nil:
+		return false
+	}
+
+	ref := reflect.ValueOf(value)
+	switch ref.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < ref.Len(); i++ {
+			if containsMatchers(ref.Index(i).Interface()) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := ref.MapRange()
+		for iter.Next() {
+			if containsMatchers(iter.Value().Interface()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// fingerprint returns hash of value which doesn't depend on map keys and array elements ordering
+func fingerprint(value interface{}) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(canonicalString(value)))
+
+	return h.Sum64()
+}
+
+// canonicalString encodes value with sorted map keys and array elements,
+// nested values are length-prefixed so no escaping is needed
+func canonicalString(value interface{}) string {
+	if value == nil {
+		return "nil"
+	}
+
+	ref := reflect.ValueOf(value)
+	switch ref.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, ref.Len())
+		for i := range items {
+			items[i] = canonicalString(ref.Index(i).Interface())
+		}
+
+		return "[" + joinCanonical(items) + "]"
+	case reflect.Map:
+		items := make([]string, 0, ref.Len())
+		iter := ref.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			val := canonicalString(iter.Value().Interface())
+			items = append(items, strconv.Itoa(len(key))+":"+key+strconv.Itoa(len(val))+":"+val)
+		}
+
+		return "{" + joinCanonical(items) + "}"
+	default:
+		return fmt.Sprintf("%T:%v", value, value)
+	}
+}
+
+func joinCanonical(items []string) string {
+	sort.Strings(items)
+
+	var b strings.Builder
+	for _, item := range items {
+		b.WriteString(strconv.Itoa(len(item)))
+		b.WriteByte(':')
+		b.WriteString(item)
+	}
+
+	return b.String()
+}
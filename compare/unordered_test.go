@@ -0,0 +1,136 @@
+package compare
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnorderedRegexDoesNotStealElement(t *testing.T) {
+	// greedy matching would pair "$matchRegexp(^a)" with "ab" and leave "ab" without pair
+	expected := []interface{}{"$matchRegexp(^a)", "ab"}
+	actual := []interface{}{"ab", "ac"}
+
+	errs := Compare(expected, actual, Params{IgnoreArraysOrdering: true})
+	assert.Empty(t, errs)
+}
+
+func TestUnorderedLooseElementDoesNotStealElement(t *testing.T) {
+	expected := unmarshalJSON(t, `[{"id": 1}, {"id": 1, "name": "$matchRegexp(^x$)"}]`)
+	actual := unmarshalJSON(t, `[{"id": 1, "name": "x"}, {"id": 1, "name": "y"}]`)
+
+	errs := Compare(expected, actual, Params{IgnoreArraysOrdering: true})
+	assert.Empty(t, errs)
+}
+
+func TestUnorderedNestedArraysOrdering(t *testing.T) {
+	expected := unmarshalJSON(t, `[{"tags": [1, 2]}, {"tags": [3, 4]}]`)
+	actual := unmarshalJSON(t, `[{"tags": [4, 3]}, {"tags": [2, 1]}]`)
+
+	errs := Compare(expected, actual, Params{IgnoreArraysOrdering: true, DisallowExtraFields: true})
+	assert.Empty(t, errs)
+}
+
+func TestUnorderedMismatch(t *testing.T) {
+	expected := unmarshalJSON(t, `[{"id": 1}, {"id": 2}, {"id": 3}]`)
+	actual := unmarshalJSON(t, `[{"id": 3}, {"id": 4}, {"id": 1}]`)
+
+	for _, strict := range []bool{false, true} {
+		errs := Compare(expected, actual, Params{IgnoreArraysOrdering: true, DisallowExtraFields: strict})
+		require.Len(t, errs, 1)
+		assert.Equal(t, makeErrorString("$[0].id", "values do not match", 2, 4), errs[0].Error())
+	}
+}
+
+func TestCanonicalStringIgnoresOrdering(t *testing.T) {
+	a := unmarshalJSON(t, `{"a": [1, {"b": "x", "c": [true, null]}], "d": "e"}`)
+	b := unmarshalJSON(t, `{"d": "e", "a": [{"c": [null, true], "b": "x"}, 1]}`)
+	c := unmarshalJSON(t, `{"d": "e", "a": [{"c": [null, true], "b": "y"}, 1]}`)
+
+	assert.Equal(t, canonicalString(a), canonicalString(b))
+	assert.NotEqual(t, canonicalString(a), canonicalString(c))
+	assert.NotEqual(t, canonicalString("1"), canonicalString(1))
+}
+
+func makeRows(n int, regexEvery int) (expected, actual []interface{}) {
+	expected = make([]interface{}, n)
+	actual = make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		row := map[string]interface{}{
+			"id":     float64(i),
+			"code":   fmt.Sprintf("ORDER%06d", i),
+			"status": "new",
+			"items":  []interface{}{float64(i % 7), float64(i % 11)},
+		}
+		actual[i] = row
+
+		exp := map[string]interface{}{}
+		for k, v := range row {
+			exp[k] = v
+		}
+		if regexEvery > 0 && i%regexEvery == 0 {
+			exp["code"] = "$matchRegexp(^ORDER\\d{6}$)"
+		}
+		expected[i] = exp
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	rnd.Shuffle(len(actual), func(i, j int) { actual[i], actual[j] = actual[j], actual[i] })
+
+	return expected, actual
+}
+
+func TestUnorderedLargeArrays(t *testing.T) {
+	expected, actual := makeRows(5000, 100)
+
+	errs := Compare(expected, actual, Params{IgnoreArraysOrdering: true})
+	assert.Empty(t, errs)
+
+	actual[42].(map[string]interface{})["status"] = "paid"
+	errs = Compare(expected, actual, Params{IgnoreArraysOrdering: true})
+	assert.Len(t, errs, 1)
+}
+
+func BenchmarkUnorderedExact(b *testing.B) {
+	expected, actual := makeRows(5000, 0)
+	params := Params{IgnoreArraysOrdering: true}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Compare(expected, actual, params)
+	}
+}
+
+func BenchmarkUnorderedExactStrict(b *testing.B) {
+	expected, actual := makeRows(5000, 0)
+	params := Params{IgnoreArraysOrdering: true, DisallowExtraFields: true}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Compare(expected, actual, params)
+	}
+}
+
+func BenchmarkUnorderedWithRegex(b *testing.B) {
+	expected, actual := makeRows(5000, 100)
+	params := Params{IgnoreArraysOrdering: true}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Compare(expected, actual, params)
+	}
+}
+
+func BenchmarkUnorderedMismatch(b *testing.B) {
+	expected, actual := makeRows(5000, 0)
+	actual[0].(map[string]interface{})["status"] = "paid"
+	params := Params{IgnoreArraysOrdering: true, DisallowExtraFields: true}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Compare(expected, actual, params)
+	}
+}
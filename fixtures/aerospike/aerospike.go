@@ -7,6 +7,8 @@ import (
 	"os"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/fixtures/substitution"
 )
 
 type aerospikeClient interface {
@@ -18,6 +20,7 @@ type LoaderAerospike struct {
 	client   aerospikeClient
 	location string
 	debug    bool
+	substitution.Substitution
 }
 
 type (
@@ -94,6 +97,9 @@ func (l *LoaderAerospike) loadFile(name string, ctx *loadContext) error {
 	if err != nil {
 		return err
 	}
+	if data, err = l.Apply(file, data); err != nil {
+		return err
+	}
 	ctx.files = append(ctx.files, file)
 
 	return l.loadYml(data, ctx)
@@ -47,6 +47,12 @@ type LoaderMultiDb interface {
 	Load(fixturesList models.FixturesMultiDb) error
 }
 
+// TemplateLoader is implemented by loaders substituting variables ({{ $name }}) into contents of fixture files,
+// loading of the file fails with the error of substitution
+type TemplateLoader interface {
+	SetSubstitution(substitute func(string) (string, error))
+}
+
 func NewLoader(cfg *Config) Loader {
 	var loader Loader
 
@@ -17,6 +17,15 @@ func New(loaders map[string]fixtures.Loader) *LoaderByMap {
 	}
 }
 
+// SetSubstitution sets function replacing variables in contents of fixture files of loaders supporting it
+func (l *LoaderByMap) SetSubstitution(substitute func(string) (string, error)) {
+	for _, loader := range l.loaders {
+		if templateLoader, ok := loader.(fixtures.TemplateLoader); ok {
+			templateLoader.SetSubstitution(substitute)
+		}
+	}
+}
+
 func (l *LoaderByMap) Load(fixturesList models.FixturesMultiDb) error {
 	for _, fixture := range fixturesList {
 		loader, ok := l.loaders[fixture.DbName]
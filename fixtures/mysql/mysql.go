@@ -12,12 +12,15 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/fixtures/substitution"
 )
 
 type LoaderMysql struct {
 	db       *sql.DB
 	location string
 	debug    bool
+	substitution.Substitution
 }
 
 const errNoIDColumnCode = 1054
@@ -104,6 +107,9 @@ func (l *LoaderMysql) loadFile(name string, ctx *loadContext) error {
 	if err != nil {
 		return err
 	}
+	if data, err = l.Apply(file, data); err != nil {
+		return err
+	}
 	ctx.files = append(ctx.files, file)
 
 	return l.loadYml(data, ctx)
@@ -12,12 +12,15 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/fixtures/substitution"
 )
 
 type LoaderPostgres struct {
 	db       *sql.DB
 	location string
 	debug    bool
+	substitution.Substitution
 }
 
 type row map[string]interface{}
@@ -120,6 +123,9 @@ func (f *LoaderPostgres) loadFile(name string, ctx *loadContext) error {
 	if err != nil {
 		return err
 	}
+	if data, err = f.Apply(file, data); err != nil {
+		return err
+	}
 	ctx.files = append(ctx.files, file)
 
 	return f.loadYml(data, ctx)
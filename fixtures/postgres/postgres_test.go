@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"database/sql"
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -231,3 +233,37 @@ func TestLoadTablesShouldExtendRows(t *testing.T) {
 	err = mock.ExpectationsWereMet()
 	require.NoError(t, err)
 }
+
+func TestLoadFileShouldSubstituteVariables(t *testing.T) {
+	ctx := loadContext{
+		refsDefinition: make(map[string]row),
+		refsInserted:   make(map[string]row),
+	}
+
+	l := New(&sql.DB{}, "../testdata", false)
+	l.SetSubstitution(func(s string) (string, error) {
+		return strings.ReplaceAll(s, "{{ $tenantId }}", "42"), nil
+	})
+	require.NoError(t, l.loadFile("sql_variables", &ctx))
+
+	query, err := l.buildInsertQuery(&ctx, newTableName("users"), ctx.tables[0].rows)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO \"public\".\"users\" AS row (\"name\", \"tenant_id\") VALUES "+
+		"('user of tenant 42', 42) RETURNING row_to_json(row)", query)
+}
+
+func TestLoadFileShouldFailOnSubstitutionError(t *testing.T) {
+	ctx := loadContext{
+		refsDefinition: make(map[string]row),
+		refsInserted:   make(map[string]row),
+	}
+
+	l := New(&sql.DB{}, "../testdata", false)
+	l.SetSubstitution(func(string) (string, error) {
+		return "", errors.New(`template: :3: function "uper" not defined`)
+	})
+	err := l.loadFile("sql_variables", &ctx)
+	require.EqualError(t, err,
+		"failed to substitute into fixture file ../testdata/sql_variables.yaml: template: :3: function \"uper\" not defined")
+	require.Empty(t, ctx.tables)
+}
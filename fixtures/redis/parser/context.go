@@ -1,11 +1,15 @@
 package parser
 
+import "github.com/lamoda/gonkey/fixtures/substitution"
+
 type Context struct {
 	keyRefs  map[string]Keys
 	hashRefs map[string]HashRecordValue
 	setRefs  map[string]SetRecordValue
 	listRefs map[string]ListRecordValue
 	zsetRefs map[string]ZSetRecordValue
+
+	substitution.Substitution
 }
 
 func NewContext() *Context {
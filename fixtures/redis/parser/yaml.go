@@ -470,6 +470,9 @@ func (p *redisYamlParser) Parse(ctx *Context, filename string) (*Fixture, error)
 	if err != nil {
 		return nil, err
 	}
+	if data, err = ctx.Apply(filename, data); err != nil {
+		return nil, err
+	}
 
 	var fixture Fixture
 	if err := yaml.Unmarshal(data, &fixture); err != nil {
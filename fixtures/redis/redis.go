@@ -6,11 +6,13 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/lamoda/gonkey/fixtures/redis/parser"
+	"github.com/lamoda/gonkey/fixtures/substitution"
 )
 
 type Loader struct {
 	locations []string
 	client    *redis.Client
+	substitution.Substitution
 }
 
 type LoaderOptions struct {
@@ -29,6 +31,7 @@ func New(opts LoaderOptions) *Loader {
 
 func (l *Loader) Load(names []string) error {
 	ctx := parser.NewContext()
+	ctx.Substitution = l.Substitution
 	fileParser := parser.New(l.locations)
 	fixtureList, err := fileParser.ParseFiles(ctx, names)
 	if err != nil {
@@ -0,0 +1,31 @@
+package substitution
+
+import (
+	"fmt"
+)
+
+// Substitution replaces variables ({{ $name }}) in contents of fixture files, loaders embed it
+// to implement fixtures.TemplateLoader
+type Substitution struct {
+	substitute func(string) (string, error)
+}
+
+// SetSubstitution sets function replacing variables in contents of fixture files
+func (s *Substitution) SetSubstitution(substitute func(string) (string, error)) {
+	s.substitute = substitute
+}
+
+// Apply returns contents of the fixture file with variables replaced, data is returned as is
+// when there is no function of substitution
+func (s *Substitution) Apply(file string, data []byte) ([]byte, error) {
+	if s.substitute == nil {
+		return data, nil
+	}
+
+	content, err := s.substitute(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to substitute into fixture file %s: %w", file, err)
+	}
+
+	return []byte(content), nil
+}
@@ -0,0 +1,30 @@
+package substitution
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	var s Substitution
+	data, err := s.Apply("users.yml", []byte("name: {{ $name }}"))
+	require.NoError(t, err)
+	assert.Equal(t, "name: {{ $name }}", string(data), "there is no function of substitution")
+
+	s.SetSubstitution(func(content string) (string, error) {
+		if content == "" {
+			return "", errors.New("empty")
+		}
+
+		return content + "!", nil
+	})
+	data, err = s.Apply("users.yml", []byte("name"))
+	require.NoError(t, err)
+	assert.Equal(t, "name!", string(data))
+
+	_, err = s.Apply("users.yml", nil)
+	assert.EqualError(t, err, "failed to substitute into fixture file users.yml: empty")
+}
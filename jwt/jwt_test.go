@@ -0,0 +1,202 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// sign creates token signed with the private key ([]byte for HMAC)
+func sign(t *testing.T, header, claims map[string]interface{}, key interface{}) string {
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	alg := header["alg"].(string)
+
+	var signature []byte
+	var err error
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(hashFunc(alg).New, k)
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		hash := hashFunc(alg)
+		if alg[0] == 'P' {
+			signature, err = rsa.SignPSS(rand.Reader, k, hash, sum(hash, []byte(input)), nil)
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, k, hash, sum(hash, []byte(input)))
+		}
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, sum(hashFunc(alg), []byte(input)))
+		size := (k.Curve.Params().BitSize + 7) / 8
+		signature = make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(input))
+	}
+	require.NoError(t, err)
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestParse(t *testing.T) {
+	raw := sign(t,
+		map[string]interface{}{"alg": "HS256", "kid": "k1"},
+		map[string]interface{}{"sub": "42", "exp": 1700000000},
+		[]byte("secret"),
+	)
+
+	token, err := Parse(raw + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, raw, token.Raw)
+	assert.Equal(t, "HS256", token.Algorithm())
+	assert.Equal(t, "k1", token.KeyID())
+	assert.Equal(t, map[string]interface{}{"sub": "42", "exp": float64(1700000000)}, token.Claims)
+
+	exp, err := token.Time("exp")
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1700000000, 0), exp)
+
+	_, err = token.Time("iat")
+	assert.EqualError(t, err, "token has no iat claim")
+	_, err = token.Time("sub")
+	assert.EqualError(t, err, "claim sub must be a number, got 42")
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse("abc")
+	assert.EqualError(t, err, "token must consist of three dot separated parts")
+
+	_, err = Parse("!!.e30.")
+	assert.ErrorContains(t, err, "invalid token header")
+
+	_, err = Parse("e30.bm90IGpzb24.")
+	assert.ErrorContains(t, err, "invalid token claims")
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		alg        string
+		privateKey interface{}
+		publicKey  interface{}
+		wrongKey   interface{}
+	}{
+		{"HS256", []byte("secret"), []byte("secret"), []byte("other")},
+		{"HS512", []byte("secret"), []byte("secret"), []byte("other")},
+		{"RS256", rsaKey, &rsaKey.PublicKey, &ecKey.PublicKey},
+		{"PS384", rsaKey, &rsaKey.PublicKey, []byte("secret")},
+		{"ES256", ecKey, &ecKey.PublicKey, &rsaKey.PublicKey},
+		{"EdDSA", edPrivate, edPublic, []byte("secret")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			raw := sign(t, map[string]interface{}{"alg": tt.alg}, map[string]interface{}{"sub": "42"}, tt.privateKey)
+			token, err := Parse(raw)
+			require.NoError(t, err)
+
+			assert.NoError(t, token.Verify(tt.publicKey))
+			assert.Error(t, token.Verify(tt.wrongKey))
+
+			tampered, err := Parse(raw[:len(raw)-4] + "AAAA")
+			require.NoError(t, err)
+			assert.Error(t, tampered.Verify(tt.publicKey))
+		})
+	}
+}
+
+func TestVerifyUnsigned(t *testing.T) {
+	token, err := Parse(encodeSegment(t, map[string]interface{}{"alg": "none"}) + "." + encodeSegment(t, map[string]interface{}{}) + ".")
+	require.NoError(t, err)
+
+	assert.EqualError(t, token.Verify([]byte("secret")), "token is not signed")
+}
+
+func TestParsePEMKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	key, err := ParsePEMKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, key)
+
+	key, err = ParsePEMKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)}))
+	require.NoError(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, key)
+
+	_, err = ParsePEMKey([]byte("not a key"))
+	assert.EqualError(t, err, "no PEM data found")
+}
+
+func TestKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "oct", "kid": "hmac", "k": %q}
+	]}`,
+		b64(rsaKey.N.Bytes()),
+		b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		b64(ecKey.X.Bytes()),
+		b64(ecKey.Y.Bytes()),
+		b64([]byte("secret")),
+	)
+
+	set, err := ParseJWKS([]byte(jwks))
+	require.NoError(t, err)
+
+	for kid, key := range map[string]interface{}{"rsa": rsaKey, "ec": ecKey, "hmac": []byte("secret")} {
+		alg := map[string]string{"rsa": "RS256", "ec": "ES256", "hmac": "HS256"}[kid]
+
+		token, err := Parse(sign(t, map[string]interface{}{"alg": alg, "kid": kid}, map[string]interface{}{}, key))
+		require.NoError(t, err)
+		assert.NoError(t, set.Verify(token), kid)
+
+		token, err = Parse(sign(t, map[string]interface{}{"alg": alg}, map[string]interface{}{}, key))
+		require.NoError(t, err)
+		assert.NoError(t, set.Verify(token), kid)
+	}
+
+	token, err := Parse(sign(t, map[string]interface{}{"alg": "HS256", "kid": "missing"}, map[string]interface{}{}, []byte("secret")))
+	require.NoError(t, err)
+	assert.EqualError(t, set.Verify(token), `key set has no key with kid "missing"`)
+
+	_, err = ParseJWKS([]byte(`{"keys": [{"kty": "unknown"}]}`))
+	assert.EqualError(t, err, `key 0: unsupported key type "unknown"`)
+}
@@ -0,0 +1,99 @@
+// Package jwt decodes JSON Web Tokens and verifies their signatures
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Token is a decoded JSON Web Token (JWS compact serialization)
+type Token struct {
+	Raw    string
+	Header map[string]interface{}
+	Claims map[string]interface{}
+
+	signingInput string
+	signature    []byte
+}
+
+// Parse decodes header and claims of the token without verifying its signature
+func Parse(raw string) (*Token, error) {
+	raw = strings.TrimSpace(raw)
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token must consist of three dot separated parts")
+	}
+
+	header, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+	claims, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	signature, err := decodeBase64(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature: %w", err)
+	}
+
+	return &Token{
+		Raw:          raw,
+		Header:       header,
+		Claims:       claims,
+		signingInput: parts[0] + "." + parts[1],
+		signature:    signature,
+	}, nil
+}
+
+// Algorithm returns alg parameter of the token header
+func (t *Token) Algorithm() string {
+	alg, _ := t.Header["alg"].(string)
+
+	return alg
+}
+
+// KeyID returns kid parameter of the token header
+func (t *Token) KeyID() string {
+	kid, _ := t.Header["kid"].(string)
+
+	return kid
+}
+
+// Time returns value of NumericDate claim (exp, iat, nbf)
+func (t *Token) Time(claim string) (time.Time, error) {
+	value, ok := t.Claims[claim]
+	if !ok {
+		return time.Time{}, fmt.Errorf("token has no %s claim", claim)
+	}
+
+	seconds, ok := value.(float64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("claim %s must be a number, got %v", claim, value)
+	}
+
+	return time.Unix(0, int64(seconds*float64(time.Second))), nil
+}
+
+func decodeSegment(segment string) (map[string]interface{}, error) {
+	data, err := decodeBase64(segment)
+	if err != nil {
+		return nil, err
+	}
+
+	var res map[string]interface{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func decodeBase64(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+}
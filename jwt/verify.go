@@ -0,0 +1,120 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrInvalidSignature is returned when signature of the token does not match the key
+var ErrInvalidSignature = errors.New("token signature is invalid")
+
+// Verify checks signature of the token with the key. Supported keys are []byte (HS256, HS384, HS512),
+// *rsa.PublicKey (RS256, RS384, RS512, PS256, PS384, PS512), *ecdsa.PublicKey (ES256, ES384, ES512)
+// and ed25519.PublicKey (EdDSA).
+func (t *Token) Verify(key interface{}) error {
+	alg := t.Algorithm()
+	input := []byte(t.signingInput)
+
+	switch alg {
+	case "HS256", "HS384", "HS512":
+		secret, ok := key.([]byte)
+		if !ok {
+			return keyTypeError(alg, key)
+		}
+		mac := hmac.New(hashFunc(alg).New, secret)
+		mac.Write(input)
+		if !hmac.Equal(mac.Sum(nil), t.signature) {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return keyTypeError(alg, key)
+		}
+		hash := hashFunc(alg)
+		digest := sum(hash, input)
+		var err error
+		if alg[0] == 'P' {
+			err = rsa.VerifyPSS(publicKey, hash, digest, t.signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+		} else {
+			err = rsa.VerifyPKCS1v15(publicKey, hash, digest, t.signature)
+		}
+		if err != nil {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	case "ES256", "ES384", "ES512":
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return keyTypeError(alg, key)
+		}
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		if len(t.signature) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(t.signature[:size])
+		s := new(big.Int).SetBytes(t.signature[size:])
+		if !ecdsa.Verify(publicKey, sum(hashFunc(alg), input), r, s) {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	case "EdDSA":
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return keyTypeError(alg, key)
+		}
+		if !ed25519.Verify(publicKey, input, t.signature) {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	case "", "none":
+		return errors.New("token is not signed")
+	default:
+		return fmt.Errorf("unsupported token algorithm %s", alg)
+	}
+}
+
+func hashFunc(alg string) crypto.Hash {
+	switch alg[2:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+func sum(hash crypto.Hash, data []byte) []byte {
+	switch hash {
+	case crypto.SHA384:
+		digest := sha512.Sum384(data)
+
+		return digest[:]
+	case crypto.SHA512:
+		digest := sha512.Sum512(data)
+
+		return digest[:]
+	default:
+		digest := sha256.Sum256(data)
+
+		return digest[:]
+	}
+}
+
+func keyTypeError(alg string, key interface{}) error {
+	return fmt.Errorf("key of type %T can not verify %s token", key, alg)
+}
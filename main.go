@@ -4,18 +4,27 @@ import (
 	"database/sql"
 	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aerospike/aerospike-client-go/v5"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/lamoda/gonkey/body_decoder"
+	"github.com/lamoda/gonkey/checker/response_binary"
 	"github.com/lamoda/gonkey/checker/response_body"
 	"github.com/lamoda/gonkey/checker/response_db"
+	"github.com/lamoda/gonkey/checker/response_header"
+	"github.com/lamoda/gonkey/checker/response_jwt"
+	"github.com/lamoda/gonkey/checker/response_metrics"
+	"github.com/lamoda/gonkey/checker/response_xpath"
+	"github.com/lamoda/gonkey/compare"
 	"github.com/lamoda/gonkey/fixtures"
 	redisLoader "github.com/lamoda/gonkey/fixtures/redis"
 	"github.com/lamoda/gonkey/output/allure_report"
@@ -38,7 +47,16 @@ type config struct {
 	AllureFormat     string
 	Verbose          bool
 	Debug            bool
+	Diff             string
 	DbType           string
+	ProtoDescriptors string
+	ProtoMessage     string
+	UpdateSnapshots  bool
+	Seed             int64
+	VarsIn           string
+	VarsOut          string
+	StrictVars       bool
+	FixtureTemplates bool
 }
 
 type storages struct {
@@ -60,12 +78,22 @@ func main() {
 		log.Fatal(err)
 	}
 
-	testsRunner := initRunner(cfg, fixturesLoader, testHandler, proxyURL)
+	vars := initVariables(cfg)
+	testsRunner := initRunner(cfg, fixturesLoader, testHandler, proxyURL, vars)
 
-	consoleOutput := console_colored.NewOutput(cfg.Verbose)
+	diffFormat, err := compare.ParseDiffFormat(cfg.Diff)
+	if err != nil {
+		log.Fatal(err)
+	}
+	consoleOutput := console_colored.NewOutput(cfg.Verbose).WithDiff(diffFormat)
 	testsRunner.AddOutput(consoleOutput)
 
-	addCheckers(testsRunner, storages.db)
+	if cfg.UpdateSnapshots {
+		testsRunner.AddSnapshotOutput(yaml_file.NewSnapshotUpdater())
+	}
+
+	registerProtobufDecoder(cfg)
+	addCheckers(testsRunner, storages.db, diffFormat)
 
 	// Setup Allure reporting based on format
 	var allureOutputV1 *allure_report.AllureReportOutput
@@ -91,6 +119,12 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if cfg.VarsOut != "" {
+		if err := vars.SaveFile(cfg.VarsOut); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// Finalize Allure reports
 	if allureOutputV1 != nil {
 		allureOutputV1.Finalize()
@@ -102,6 +136,7 @@ func main() {
 	summary := testHandler.Summary()
 	consoleOutput.ShowSummary(summary)
 	if !summary.Success {
+		fmt.Printf("Generated values can be replayed with -seed %d\n", cfg.Seed)
 		os.Exit(1)
 	}
 }
@@ -156,6 +191,10 @@ func validateConfig(cfg *config) {
 	}
 	cfg.Host = strings.TrimRight(cfg.Host, "/")
 
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+
 	if cfg.TestsLocation == "" {
 		log.Fatal(errors.New("no tests location provided"))
 	}
@@ -167,8 +206,25 @@ func validateConfig(cfg *config) {
 	}
 }
 
-func addCheckers(r *runner.Runner, db *sql.DB) {
-	r.AddCheckers(response_body.NewChecker())
+func registerProtobufDecoder(cfg config) {
+	if cfg.ProtoDescriptors == "" {
+		return
+	}
+
+	decoder, err := body_decoder.NewProtobufDecoderFromFile(cfg.ProtoDescriptors, cfg.ProtoMessage)
+	if err != nil {
+		log.Fatal(err)
+	}
+	body_decoder.Register(decoder, body_decoder.ProtobufMediaTypes...)
+}
+
+func addCheckers(r *runner.Runner, db *sql.DB, diffFormat compare.DiffFormat) {
+	r.AddCheckers(response_body.NewCheckerWithDiff(diffFormat))
+	r.AddCheckers(response_header.NewChecker())
+	r.AddCheckers(response_xpath.NewChecker())
+	r.AddCheckers(response_binary.NewChecker())
+	r.AddCheckers(response_jwt.NewChecker())
+	r.AddCheckers(response_metrics.NewChecker())
 	if db != nil {
 		r.AddCheckers(response_db.NewChecker(db))
 	}
@@ -179,19 +235,37 @@ func initRunner(
 	fixturesLoader fixtures.Loader,
 	handler *runner.ConsoleHandler,
 	proxyURL *url.URL,
+	vars *variables.Variables,
 ) *runner.Runner {
 	return runner.New(
 		&runner.Config{
-			Host:           cfg.Host,
-			FixturesLoader: fixturesLoader,
-			Variables:      variables.New(),
-			HTTPProxyURL:   proxyURL,
+			Host:             cfg.Host,
+			FixturesLoader:   fixturesLoader,
+			Variables:        vars,
+			HTTPProxyURL:     proxyURL,
+			Debug:            cfg.Debug,
+			StrictVariables:  cfg.StrictVars,
+			FixtureTemplates: cfg.FixtureTemplates,
 		},
 		yaml_file.NewLoader(cfg.TestsLocation),
 		handler.HandleTest,
 	)
 }
 
+// initVariables creates variables with the seed of generator functions and initial variables of -vars-in file
+func initVariables(cfg config) *variables.Variables {
+	vars := variables.New()
+	vars.SetSeed(cfg.Seed)
+
+	if cfg.VarsIn != "" {
+		if err := vars.LoadFile(cfg.VarsIn); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	return vars
+}
+
 func initAerospike(cfg config) *aerospikeAdapter.Client {
 	if cfg.AerospikeHost != "" {
 		address, port, namespace := parseAerospikeHost(cfg.AerospikeHost)
@@ -239,6 +313,20 @@ func getConfig() config {
 	flag.StringVar(&cfg.AllureFormat, "allure-format", "v2", "Allure report format: v1/xml (legacy) or v2/json (default)")
 	flag.BoolVar(&cfg.Verbose, "v", false, "Verbose output")
 	flag.BoolVar(&cfg.Debug, "debug", false, "Debug output")
+	flag.StringVar(&cfg.Diff, "diff", "", "Show diff of expected and actual bodies on mismatch: unified or side-by-side")
+	flag.StringVar(
+		&cfg.ProtoDescriptors,
+		"proto-descriptor-set",
+		"",
+		"Path to protobuf descriptor set (protoc --include_imports --descriptor_set_out) to decode protobuf responses",
+	)
+	flag.StringVar(&cfg.ProtoMessage, "proto-message", "", "Default full name of protobuf response message")
+	flag.BoolVar(&cfg.UpdateSnapshots, "update-snapshots", false, "Rewrite expected responses of failed tests with actual ones")
+	flag.Int64Var(&cfg.Seed, "seed", 0, "Seed of generator functions ($uuid(), $randInt(), $fake.email, etc.), random by default")
+	flag.StringVar(&cfg.VarsIn, "vars-in", "", "Path to JSON file or env-file with variables loaded before the run")
+	flag.StringVar(&cfg.VarsOut, "vars-out", "", "Path to JSON file the variables are written to after the run")
+	flag.BoolVar(&cfg.StrictVars, "strict-vars", false, "Fail tests with variables which are not defined and have no default value")
+	flag.BoolVar(&cfg.FixtureTemplates, "fixture-templates", false, "Render contents of fixture files as templates with template functions")
 	flag.StringVar(
 		&cfg.DbType,
 		"db-type",
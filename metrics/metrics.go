@@ -0,0 +1,256 @@
+// Package metrics parses Prometheus text exposition format and selects samples by name and labels
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sample is a single value of the metric with labels
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Samples are all samples exposed by the service
+type Samples []Sample
+
+// Parse reads samples in Prometheus text exposition format, comments and timestamps are skipped
+func Parse(r io.Reader) (Samples, error) {
+	var res Samples
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		sample, err := parseSample(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		res = append(res, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Sum returns sum of values of samples matching the selector, false is returned if there are no such samples
+func (s Samples) Sum(selector Selector) (float64, bool) {
+	var sum float64
+	found := false
+	for _, sample := range s {
+		if selector.Matches(sample) {
+			sum += sample.Value
+			found = true
+		}
+	}
+
+	return sum, found
+}
+
+// Selector selects samples by name and subset of labels: orders_created_total{status="ok"}
+type Selector struct {
+	Name   string
+	Labels map[string]string
+}
+
+// ParseSelector parses selector written as metric name with optional labels in curly braces
+func ParseSelector(str string) (Selector, error) {
+	str = strings.TrimSpace(str)
+	name, labels, err := parseSeries(str)
+	if err != nil {
+		return Selector{}, fmt.Errorf("invalid metric selector %s: %w", str, err)
+	}
+
+	return Selector{Name: name, Labels: labels}, nil
+}
+
+// Matches reports whether the sample has name of the selector and all of its labels
+func (s Selector) Matches(sample Sample) bool {
+	if sample.Name != s.Name {
+		return false
+	}
+	for name, value := range s.Labels {
+		if sample.Labels[name] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s Selector) String() string {
+	if len(s.Labels) == 0 {
+		return s.Name
+	}
+
+	names := make([]string, 0, len(s.Labels))
+	for name := range s.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=%q", name, s.Labels[name])
+	}
+
+	return s.Name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func parseSample(text string) (Sample, error) {
+	// value follows the name or closing brace of labels, timestamp may follow the value
+	end := strings.LastIndex(text, "}")
+	if end == -1 {
+		end = strings.IndexAny(text, " \t")
+		if end == -1 {
+			return Sample{}, fmt.Errorf("sample has no value: %s", text)
+		}
+	} else {
+		end++
+	}
+
+	name, labels, err := parseSeries(text[:end])
+	if err != nil {
+		return Sample{}, err
+	}
+
+	fields := strings.Fields(text[end:])
+	if len(fields) == 0 || len(fields) > 2 {
+		return Sample{}, fmt.Errorf("invalid sample: %s", text)
+	}
+	value, err := parseValue(fields[0])
+	if err != nil {
+		return Sample{}, err
+	}
+
+	return Sample{Name: name, Labels: labels, Value: value}, nil
+}
+
+func parseValue(str string) (float64, error) {
+	switch str {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample value %s", str)
+	}
+
+	return value, nil
+}
+
+// parseSeries parses metric name with optional labels: name{label="value",...}
+func parseSeries(str string) (string, map[string]string, error) {
+	open := strings.Index(str, "{")
+	if open == -1 {
+		if !isName(str) {
+			return "", nil, fmt.Errorf("invalid metric name %q", str)
+		}
+
+		return str, map[string]string{}, nil
+	}
+
+	name := strings.TrimSpace(str[:open])
+	if !isName(name) {
+		return "", nil, fmt.Errorf("invalid metric name %q", name)
+	}
+	if !strings.HasSuffix(str, "}") {
+		return "", nil, fmt.Errorf("labels of %s are not closed", name)
+	}
+
+	labels, err := parseLabels(str[open+1 : len(str)-1])
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, labels, nil
+}
+
+func parseLabels(str string) (map[string]string, error) {
+	labels := map[string]string{}
+	for {
+		str = strings.TrimLeft(str, " \t,")
+		if str == "" {
+			return labels, nil
+		}
+
+		eq := strings.Index(str, "=")
+		if eq == -1 {
+			return nil, fmt.Errorf("label has no value: %s", str)
+		}
+		name := strings.TrimSpace(str[:eq])
+		if !isName(name) {
+			return nil, fmt.Errorf("invalid label name %q", name)
+		}
+
+		value, rest, err := parseQuoted(strings.TrimLeft(str[eq+1:], " \t"))
+		if err != nil {
+			return nil, fmt.Errorf("label %s: %w", name, err)
+		}
+		labels[name] = value
+		str = rest
+	}
+}
+
+// parseQuoted reads double-quoted label value with \\, \" and \n escapes
+func parseQuoted(str string) (string, string, error) {
+	if !strings.HasPrefix(str, `"`) {
+		return "", "", fmt.Errorf("value must be quoted")
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(str); i++ {
+		switch c := str[i]; c {
+		case '"':
+			return b.String(), str[i+1:], nil
+		case '\\':
+			i++
+			if i == len(str) {
+				return "", "", fmt.Errorf("value is not closed")
+			}
+			if str[i] == 'n' {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(str[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", "", fmt.Errorf("value is not closed")
+}
+
+func isName(str string) bool {
+	if str == "" {
+		return false
+	}
+	for i, c := range str {
+		letter := c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !letter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exposition = `
+# HELP orders_created_total Orders created.
+# TYPE orders_created_total counter
+orders_created_total{status="ok",region="eu"} 3
+orders_created_total{status="ok",region="us"} 2 1700000000000
+orders_created_total{status="failed", region="eu"} 1
+# TYPE queue_size gauge
+queue_size 7.5
+request_duration_seconds_bucket{le="+Inf"} 12
+label_escapes{path="C:\\tmp\"x\"\nend"} 1
+upper_bound +Inf
+`
+
+func TestParse(t *testing.T) {
+	samples, err := Parse(strings.NewReader(exposition))
+	require.NoError(t, err)
+	require.Len(t, samples, 7)
+
+	assert.Equal(t, Sample{
+		Name:   "orders_created_total",
+		Labels: map[string]string{"status": "ok", "region": "us"},
+		Value:  2,
+	}, samples[1])
+	assert.Equal(t, Sample{Name: "queue_size", Labels: map[string]string{}, Value: 7.5}, samples[3])
+	assert.Equal(t, map[string]string{"le": "+Inf"}, samples[4].Labels)
+	assert.Equal(t, "C:\\tmp\"x\"\nend", samples[5].Labels["path"])
+	assert.True(t, math.IsInf(samples[6].Value, 1))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{
+		"orders_created_total",
+		"orders_created_total{status=\"ok\" 1",
+		"orders_created_total{status=ok} 1",
+		"orders_created_total one",
+		"1orders 1",
+	} {
+		_, err := Parse(strings.NewReader(text))
+		assert.Error(t, err, text)
+	}
+}
+
+func TestSum(t *testing.T) {
+	samples, err := Parse(strings.NewReader(exposition))
+	require.NoError(t, err)
+
+	tests := []struct {
+		selector string
+		sum      float64
+		found    bool
+	}{
+		{`orders_created_total{status="ok"}`, 5, true},
+		{`orders_created_total{status="ok", region="eu"}`, 3, true},
+		{`orders_created_total`, 6, true},
+		{`orders_created_total{status="unknown"}`, 0, false},
+		{`queue_size`, 7.5, true},
+		{`missing_total`, 0, false},
+	}
+
+	for _, tt := range tests {
+		selector, err := ParseSelector(tt.selector)
+		require.NoError(t, err)
+
+		sum, found := samples.Sum(selector)
+		assert.Equal(t, tt.found, found, tt.selector)
+		assert.Equal(t, tt.sum, sum, tt.selector)
+	}
+}
+
+func TestSelectorString(t *testing.T) {
+	selector, err := ParseSelector(` orders_created_total{status="ok",region="eu"} `)
+	require.NoError(t, err)
+	assert.Equal(t, `orders_created_total{region="eu",status="ok"}`, selector.String())
+
+	_, err = ParseSelector(`orders created`)
+	assert.EqualError(t, err, `invalid metric selector orders created: invalid metric name "orders created"`)
+}
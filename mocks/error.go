@@ -3,6 +3,8 @@ package mocks
 import (
 	"fmt"
 	"reflect"
+
+	"github.com/lamoda/gonkey/variables"
 )
 
 type Error struct {
@@ -34,6 +36,12 @@ func (e *RequestConstraintError) Unwrap() error {
 	return e.error
 }
 
+// mask hides secret values in the request dump and in the message of the error
+func (e *RequestConstraintError) mask(mask func(string) string) {
+	e.RequestDump = []byte(mask(string(e.RequestDump)))
+	e.error = variables.MaskError(e.error, mask)
+}
+
 type CallsMismatchError struct {
 	Path        string
 	Expected    int
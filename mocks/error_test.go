@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"errors"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -116,3 +118,23 @@ func TestError_UnwrapChain(t *testing.T) {
 	assert.Equal(t, models.ErrorCategoryMock, extractedErr.GetCategory())
 	assert.Equal(t, "my-service", extractedErr.GetIdentifier())
 }
+
+func TestMocks_EndRunningContextMasksRequestDump(t *testing.T) {
+	constraint, err := newHeaderConstraint("Authorization", "Bearer expected", "")
+	require.NoError(t, err)
+	m := New(NewServiceMock("auth", NewDefinition("$", []verifier{constraint}, &failReply{}, CallsNoConstraint)))
+	m.SetMask(func(s string) string { return strings.ReplaceAll(s, "tok-1", "***") })
+
+	r := httptest.NewRequest("GET", "/profile", nil)
+	r.Header.Set("Authorization", "Bearer tok-1")
+	m.Service("auth").ServeHTTP(httptest.NewRecorder(), r)
+
+	errs := m.EndRunningContext()
+	require.NotEmpty(t, errs)
+
+	var constraintErr *RequestConstraintError
+	require.True(t, errors.As(errs[0], &constraintErr))
+	assert.Contains(t, string(constraintErr.RequestDump), "Authorization: Bearer ***")
+	assert.NotContains(t, errs[0].Error(), "tok-1")
+	assert.NotContains(t, constraintErr.Unwrap().Error(), "tok-1")
+}
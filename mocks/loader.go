@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strconv"
 
+	"gopkg.in/yaml.v2"
+
 	"github.com/lamoda/gonkey/compare"
 )
 
@@ -342,7 +344,7 @@ func (l *Loader) loadConstraintOfKind(kind string, def map[interface{}]interface
 		*ak = append(*ak, "method")
 		return l.loadMethodIsConstraint(def)
 	case "headerIs":
-		*ak = append(*ak, "header", "value", "regexp")
+		*ak = append(*ak, "header", "value", "regexp", "ignoreValuesOrdering")
 		return l.loadHeaderIsConstraint(def)
 	case "bodyMatchesText":
 		*ak = append(*ak, "body", "regexp")
@@ -353,6 +355,9 @@ func (l *Loader) loadConstraintOfKind(kind string, def map[interface{}]interface
 	case "bodyMatchesXML":
 		*ak = append(*ak, "body", "comparisonParams")
 		return l.loadBodyMatchesXMLConstraint(def)
+	case "bodyXPathMatches":
+		*ak = append(*ak, "xpath", "value", "exists", "namespaces")
+		return l.loadBodyXPathMatchesConstraint(def)
 	default:
 		return nil, fmt.Errorf("unknown constraint: %s", kind)
 	}
@@ -373,29 +378,16 @@ func readCompareParams(def map[interface{}]interface{}) (compare.Params, error)
 		return params, errors.New("`comparisonParams` can't be parsed")
 	}
 
-	mapping := map[string]*bool{
-		"ignoreValues":         &params.IgnoreValues,
-		"ignoreArraysOrdering": &params.IgnoreArraysOrdering,
-		"disallowExtraFields":  &params.DisallowExtraFields,
+	// re-decode raw values to get nested params (ignorePaths, overrides) parsed the same way as in tests
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return params, fmt.Errorf("`comparisonParams` can't be parsed: %w", err)
 	}
-
-	for key, val := range values {
-		skey, ok := key.(string)
-		if !ok {
-			return params, errors.New("`comparisonParams` has non-string key")
-		}
-
-		bval, ok := val.(bool)
-		if !ok {
-			return params, errors.New("`comparisonParams` has non-bool values")
-		}
-
-		if pbval, ok := mapping[skey]; ok {
-			*pbval = bval
-		}
-
+	if err := yaml.Unmarshal(data, &params); err != nil {
+		return params, fmt.Errorf("`comparisonParams` can't be parsed: %w", err)
 	}
-	return params, nil
+
+	return params, params.Validate()
 }
 
 func (l *Loader) loadBodyMatchesJSONConstraint(def map[interface{}]interface{}) (verifier, error) {
@@ -461,6 +453,43 @@ func (l *Loader) loadBodyMatchesXMLConstraint(def map[interface{}]interface{}) (
 	return newBodyMatchesXMLConstraint(body, params)
 }
 
+func (l *Loader) loadBodyXPathMatchesConstraint(def map[interface{}]interface{}) (verifier, error) {
+	c, ok := def["xpath"]
+	if !ok {
+		return nil, errors.New("`bodyXPathMatches` requires `xpath` key")
+	}
+	path, ok := c.(string)
+	if !ok || path == "" {
+		return nil, errors.New("`xpath` must be string")
+	}
+
+	exists := true
+	if c, ok := def["exists"]; ok {
+		exists, ok = c.(bool)
+		if !ok {
+			return nil, errors.New("`exists` must be bool")
+		}
+	}
+
+	namespaces := map[string]string{}
+	if c, ok := def["namespaces"]; ok {
+		values, ok := c.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.New("`namespaces` must be map")
+		}
+		for prefix, uri := range values {
+			prefixStr, ok1 := prefix.(string)
+			uriStr, ok2 := uri.(string)
+			if !ok1 || !ok2 {
+				return nil, errors.New("`namespaces` must map prefixes to URIs")
+			}
+			namespaces[prefixStr] = uriStr
+		}
+	}
+
+	return newBodyXPathMatchesConstraint(path, def["value"], exists, namespaces)
+}
+
 func (l *Loader) loadPathMatchesConstraint(def map[interface{}]interface{}) (verifier, error) {
 	var pathStr, regexpStr string
 	if path, ok := def["path"]; ok {
@@ -523,11 +552,14 @@ func (l *Loader) loadHeaderIsConstraint(def map[interface{}]interface{}) (verifi
 	if !ok || header == "" {
 		return nil, errors.New("`header` must be string")
 	}
+	if values, ok := def["value"].([]interface{}); ok {
+		return loadHeaderValuesConstraint(header, values, def)
+	}
 	var valueStr, regexpStr string
 	if value, ok := def["value"]; ok {
 		valueStr, ok = value.(string)
 		if !ok {
-			return nil, errors.New("`value` must be string")
+			return nil, errors.New("`value` must be string or list of strings")
 		}
 	}
 	if regexp, ok := def["regexp"]; ok {
@@ -539,6 +571,28 @@ func (l *Loader) loadHeaderIsConstraint(def map[interface{}]interface{}) (verifi
 	return newHeaderConstraint(header, valueStr, regexpStr)
 }
 
+func loadHeaderValuesConstraint(header string, values []interface{}, def map[interface{}]interface{}) (verifier, error) {
+	if _, ok := def["regexp"]; ok {
+		return nil, errors.New("`regexp` can't be used with list of values")
+	}
+	valuesStr := make([]string, len(values))
+	for i, v := range values {
+		str, ok := v.(string)
+		if !ok {
+			return nil, errors.New("`value` must be string or list of strings")
+		}
+		valuesStr[i] = str
+	}
+	ignoreOrdering := false
+	if c, ok := def["ignoreValuesOrdering"]; ok {
+		ignoreOrdering, ok = c.(bool)
+		if !ok {
+			return nil, errors.New("`ignoreValuesOrdering` must be bool")
+		}
+	}
+	return newHeaderValuesConstraint(header, valuesStr, ignoreOrdering), nil
+}
+
 func (l *Loader) loadBodyMatchesTextConstraint(def map[interface{}]interface{}) (verifier, error) {
 	var bodyStr, regexpStr string
 	if body, ok := def["body"]; ok {
@@ -9,6 +9,7 @@ import (
 
 type Mocks struct {
 	mocks map[string]*ServiceMock
+	mask  func(string) string
 }
 
 func New(mocks ...*ServiceMock) *Mocks {
@@ -83,12 +84,25 @@ func (m *Mocks) ResetRunningContext() {
 	}
 }
 
+// SetMask sets function hiding secret values in request dumps and messages of failed request constraints
+func (m *Mocks) SetMask(mask func(string) string) {
+	m.mask = mask
+}
+
 func (m *Mocks) EndRunningContext() []error {
-	var errors []error
+	var errs []error
 	for _, v := range m.mocks {
-		errors = append(errors, v.EndRunningContext()...)
+		errs = append(errs, v.EndRunningContext()...)
+	}
+	if m.mask != nil {
+		for _, err := range errs {
+			var constraintErr *RequestConstraintError
+			if errors.As(err, &constraintErr) {
+				constraintErr.mask(m.mask)
+			}
+		}
 	}
-	return errors
+	return errs
 }
 
 func (m *Mocks) GetNames() []string {
@@ -66,6 +66,67 @@ func (c *bodyMatchesXMLConstraint) Verify(r *http.Request) []error {
 	return compare.Compare(c.expectedBody, actual, c.compareParams)
 }
 
+type bodyXPathMatchesConstraint struct {
+	xpath      string
+	value      interface{}
+	exists     bool
+	namespaces map[string]string
+}
+
+func newBodyXPathMatchesConstraint(path string, value interface{}, exists bool, namespaces map[string]string) (verifier, error) {
+	if err := xmlparsing.ValidateXPath(path, namespaces); err != nil {
+		return nil, err
+	}
+
+	res := &bodyXPathMatchesConstraint{
+		xpath:      path,
+		value:      value,
+		exists:     exists,
+		namespaces: namespaces,
+	}
+	return res, nil
+}
+
+func (c *bodyXPathMatchesConstraint) Verify(r *http.Request) []error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return []error{err}
+	}
+	// write body for future reusing
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) == 0 {
+		return []error{errors.New("request is empty")}
+	}
+
+	doc, err := xmlparsing.ParseDocument(string(body))
+	if err != nil {
+		return []error{err}
+	}
+	res, err := doc.Query(c.xpath, c.namespaces)
+	if err != nil {
+		return []error{err}
+	}
+
+	switch {
+	case !c.exists && res.Exists():
+		return []error{fmt.Errorf("xpath %s is expected to select no nodes", c.xpath)}
+	case !c.exists:
+		return nil
+	case !res.Exists():
+		return []error{fmt.Errorf("xpath %s does not select any node", c.xpath)}
+	case c.value == nil:
+		return nil
+	}
+
+	expected, actual := res.Comparable(c.value)
+	errs := compare.Compare(expected, actual, compare.Params{})
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("xpath %s: %w", c.xpath, err)
+	}
+
+	return errs
+}
+
 type bodyMatchesJSONConstraint struct {
 	expectedBody  interface{}
 	compareParams compare.Params
@@ -158,10 +219,16 @@ func (c *methodConstraint) Verify(r *http.Request) []error {
 	return nil
 }
 
+// absentHeader is expected value of a header which must not be present in the request
+const absentHeader = "$absent"
+
 type headerConstraint struct {
 	header string
 	value  string
-	regexp *regexp.Regexp
+	// values are expected values of all header lines, order is ignored if ignoreOrdering is set
+	values         []string
+	ignoreOrdering bool
+	regexp         *regexp.Regexp
 }
 
 func newHeaderConstraint(header, value, re string) (verifier, error) {
@@ -181,11 +248,28 @@ func newHeaderConstraint(header, value, re string) (verifier, error) {
 	return res, nil
 }
 
+func newHeaderValuesConstraint(header string, values []string, ignoreOrdering bool) verifier {
+	return &headerConstraint{
+		header:         header,
+		values:         values,
+		ignoreOrdering: ignoreOrdering,
+	}
+}
+
 func (c *headerConstraint) Verify(r *http.Request) []error {
 	value := r.Header.Get(c.header)
+	if c.value == absentHeader {
+		if len(r.Header.Values(c.header)) > 0 {
+			return []error{fmt.Errorf("request has header %s which is expected to be absent", c.header)}
+		}
+		return nil
+	}
 	if value == "" {
 		return []error{fmt.Errorf("request doesn't have header %s", c.header)}
 	}
+	if c.values != nil {
+		return c.verifyValues(r.Header.Values(c.header))
+	}
 	if c.value != "" && c.value != value {
 		return []error{fmt.Errorf("%s header value %s doesn't match expected %s", c.header, value, c.value)}
 	}
@@ -195,6 +279,22 @@ func (c *headerConstraint) Verify(r *http.Request) []error {
 	return nil
 }
 
+func (c *headerConstraint) verifyValues(actual []string) []error {
+	expectedValues := make([]interface{}, len(c.values))
+	for i, v := range c.values {
+		expectedValues[i] = v
+	}
+	actualValues := make([]interface{}, len(actual))
+	for i, v := range actual {
+		actualValues[i] = v
+	}
+
+	if errs := compare.Compare(expectedValues, actualValues, compare.Params{IgnoreArraysOrdering: c.ignoreOrdering}); len(errs) > 0 {
+		return []error{fmt.Errorf("%s header values %v don't match expected %v", c.header, actual, c.values)}
+	}
+	return nil
+}
+
 type queryConstraint struct {
 	expectedQuery url.Values
 }
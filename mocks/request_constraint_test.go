@@ -4,7 +4,10 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v2"
 )
 
 func Test_newQueryConstraint(t *testing.T) {
@@ -206,6 +209,128 @@ func Test_queryRegexpConstraint_Verify(t *testing.T) {
 	}
 }
 
+func Test_bodyXPathMatchesConstraint_Verify(t *testing.T) {
+	const body = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+		<soap:Body><Order xmlns="urn:shop" id="42"><Item>apple</Item><Item>pear</Item></Order></soap:Body>
+	</soap:Envelope>`
+
+	tests := []struct {
+		name       string
+		definition string
+		wantErrors int
+	}{
+		{
+			name: "value matches",
+			definition: "{kind: bodyXPathMatches, xpath: '/s:Envelope/s:Body/m:Order/@id', value: 42, " +
+				"namespaces: {s: 'http://schemas.xmlsoap.org/soap/envelope/', m: 'urn:shop'}}",
+			wantErrors: 0,
+		},
+		{
+			name:       "all nodes match",
+			definition: "{kind: bodyXPathMatches, xpath: '//m:Item', value: [apple, '$matchRegexp(^p)'], namespaces: {m: 'urn:shop'}}",
+			wantErrors: 0,
+		},
+		{
+			name:       "value does not match",
+			definition: "{kind: bodyXPathMatches, xpath: 'count(//m:Item)', value: 3, namespaces: {m: 'urn:shop'}}",
+			wantErrors: 1,
+		},
+		{
+			name:       "node exists",
+			definition: "{kind: bodyXPathMatches, xpath: '//m:Order', namespaces: {m: 'urn:shop'}}",
+			wantErrors: 0,
+		},
+		{
+			name:       "node does not exist",
+			definition: "{kind: bodyXPathMatches, xpath: '//m:Discount', namespaces: {m: 'urn:shop'}}",
+			wantErrors: 1,
+		},
+		{
+			name:       "node is expected to be absent",
+			definition: "{kind: bodyXPathMatches, xpath: '//m:Order', exists: false, namespaces: {m: 'urn:shop'}}",
+			wantErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var def map[interface{}]interface{}
+			if err := yaml.Unmarshal([]byte(tt.definition), &def); err != nil {
+				t.Fatal(err)
+			}
+			c, err := NewLoader(nil).loadConstraint(def)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r, _ := http.NewRequest("POST", "http://localhost/", strings.NewReader(body))
+			if gotErrors := c.Verify(r); len(gotErrors) != tt.wantErrors {
+				t.Errorf("unexpected amount of errors. Got %v, want %v. Errors are: '%v'",
+					len(gotErrors), tt.wantErrors, gotErrors,
+				)
+			}
+		})
+	}
+}
+
+func Test_headerConstraint_Verify(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		wantErrors int
+	}{
+		{name: "absent header", definition: "{kind: headerIs, header: X-Debug, value: $absent}", wantErrors: 0},
+		{name: "present header expected to be absent", definition: "{kind: headerIs, header: Accept, value: $absent}", wantErrors: 1},
+		{name: "all values", definition: "{kind: headerIs, header: Accept, value: [text/html, '$matchRegexp(json)']}", wantErrors: 0},
+		{name: "all values in other order", definition: "{kind: headerIs, header: Accept, value: [application/json, text/html]}", wantErrors: 1},
+		{
+			name:       "all values ignoring ordering",
+			definition: "{kind: headerIs, header: Accept, value: [application/json, text/html], ignoreValuesOrdering: true}",
+			wantErrors: 0,
+		},
+		{name: "part of values", definition: "{kind: headerIs, header: Accept, value: [text/html]}", wantErrors: 1},
+		{name: "single value", definition: "{kind: headerIs, header: Accept, value: text/html}", wantErrors: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var def map[interface{}]interface{}
+			if err := yaml.Unmarshal([]byte(tt.definition), &def); err != nil {
+				t.Fatal(err)
+			}
+			c, err := NewLoader(nil).loadConstraint(def)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r, _ := http.NewRequest("GET", "http://localhost/", nil)
+			r.Header.Add("Accept", "text/html")
+			r.Header.Add("Accept", "application/json")
+			if gotErrors := c.Verify(r); len(gotErrors) != tt.wantErrors {
+				t.Errorf("unexpected amount of errors. Got %v, want %v. Errors are: '%v'",
+					len(gotErrors), tt.wantErrors, gotErrors,
+				)
+			}
+		})
+	}
+}
+
+func Test_loadBodyXPathMatchesConstraint_invalid(t *testing.T) {
+	definitions := []string{
+		"{kind: bodyXPathMatches}",
+		"{kind: bodyXPathMatches, xpath: '//m:Order'}",
+		"{kind: bodyXPathMatches, xpath: '//Order', exists: maybe}",
+		"{kind: bodyXPathMatches, xpath: '//Order', namespaces: [m]}",
+	}
+	for _, definition := range definitions {
+		var def map[interface{}]interface{}
+		if err := yaml.Unmarshal([]byte(definition), &def); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewLoader(nil).loadConstraint(def); err == nil {
+			t.Errorf("expected error for %s", definition)
+		}
+	}
+}
+
 func newTestRequest(query string) *http.Request {
 	r, _ := http.NewRequest("GET", "http://localhost/?"+query, nil)
 	return r
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"sync"
 	"text/template"
+
+	"github.com/lamoda/gonkey/template_funcs"
 )
 
 type templateReply struct {
@@ -40,7 +42,7 @@ func (tr *templateRequest) Json() (map[string]interface{}, error) {
 }
 
 func newTemplateReply(content string, statusCode int, headers map[string]string) (ReplyStrategy, error) {
-	res, err := template.New("").Parse(content)
+	res, err := template_funcs.New("").Parse(content)
 	if err != nil {
 		return nil, fmt.Errorf("template syntax error: %w", err)
 	}
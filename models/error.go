@@ -1,6 +1,12 @@
 package models
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lamoda/gonkey/compare"
+)
 
 // ErrorCategory defines the type of check that failed
 type ErrorCategory string
@@ -9,8 +15,10 @@ const (
 	ErrorCategoryStatusCode     ErrorCategory = "status_code"
 	ErrorCategoryResponseBody   ErrorCategory = "body"
 	ErrorCategoryResponseHeader ErrorCategory = "header"
+	ErrorCategoryResponseBinary ErrorCategory = "binary"
 	ErrorCategoryDatabase       ErrorCategory = "database"
 	ErrorCategoryMock           ErrorCategory = "mock"
+	ErrorCategoryMetrics        ErrorCategory = "metrics"
 )
 
 // CheckError represents a typed error from a specific check
@@ -22,11 +30,24 @@ type CheckError struct {
 }
 
 func (e *CheckError) Error() string {
-	if e.Err != nil {
+	switch {
+	case e.Err != nil && e.Message != "":
 		return fmt.Sprintf("%s: %s", e.Message, e.Err.Error())
+	case e.Err != nil:
+		return e.Err.Error()
+	default:
+		return e.Message
+	}
+}
+
+// Mismatch returns comparison details if the error was caused by compare.Compare
+func (e *CheckError) Mismatch() (*compare.MismatchError, bool) {
+	var mismatch *compare.MismatchError
+	if errors.As(e.Err, &mismatch) {
+		return mismatch, true
 	}
 
-	return e.Message
+	return nil, false
 }
 
 func (e *CheckError) Unwrap() error {
@@ -48,6 +69,21 @@ func NewStatusCodeError(expected, actual int) error {
 	}
 }
 
+// NewStatusCodesError reports status code not matching any of expected codes and classes of codes (2xx)
+func NewStatusCodesError(expected []string, actual int) error {
+	if len(expected) == 1 {
+		return &CheckError{
+			Category: ErrorCategoryStatusCode,
+			Message:  fmt.Sprintf("status code mismatch: expected %s, got %d", expected[0], actual),
+		}
+	}
+
+	return &CheckError{
+		Category: ErrorCategoryStatusCode,
+		Message:  fmt.Sprintf("status code mismatch: expected one of %s, got %d", strings.Join(expected, ", "), actual),
+	}
+}
+
 func NewBodyError(msg string, args ...interface{}) error {
 	return &CheckError{
 		Category: ErrorCategoryResponseBody,
@@ -63,6 +99,56 @@ func NewBodyErrorWithCause(err error, msg string, args ...interface{}) error {
 	}
 }
 
+// NewBodyMismatchError wraps an error returned by compare.Compare,
+// path of the mismatch is used as identifier
+func NewBodyMismatchError(err error) error {
+	checkErr := &CheckError{
+		Category: ErrorCategoryResponseBody,
+		Err:      err,
+	}
+	if mismatch, ok := checkErr.Mismatch(); ok {
+		checkErr.Identifier = mismatch.Path
+	}
+
+	return checkErr
+}
+
+// NewXPathError reports failed XPath assertion, XPath expression is used as identifier
+func NewXPathError(xpath string, err error) error {
+	return &CheckError{
+		Category:   ErrorCategoryResponseBody,
+		Identifier: xpath,
+		Message:    fmt.Sprintf("xpath %s", xpath),
+		Err:        err,
+	}
+}
+
+// NewJWTError reports failed JWT assertion, location of the token is used as identifier
+func NewJWTError(location string, err error) error {
+	return &CheckError{
+		Category:   ErrorCategoryResponseBody,
+		Identifier: location,
+		Message:    fmt.Sprintf("jwt %s", location),
+		Err:        err,
+	}
+}
+
+func NewBinaryError(msg string, args ...interface{}) error {
+	return &CheckError{
+		Category: ErrorCategoryResponseBinary,
+		Message:  fmt.Sprintf(msg, args...),
+	}
+}
+
+// NewMetricsError reports failed metrics check, selector of the metric is used as identifier
+func NewMetricsError(selector, msg string, args ...interface{}) error {
+	return &CheckError{
+		Category:   ErrorCategoryMetrics,
+		Identifier: selector,
+		Message:    fmt.Sprintf("metric %s: %s", selector, fmt.Sprintf(msg, args...)),
+	}
+}
+
 func NewHeaderError(msg string, args ...interface{}) error {
 	return &CheckError{
 		Category: ErrorCategoryResponseHeader,
@@ -93,6 +179,15 @@ func NewDatabaseErrorWithIdentifier(queryIndex int, msg string, args ...interfac
 	}
 }
 
+// NewDatabaseMismatchError wraps an error returned by compare.Compare for the query with given index
+func NewDatabaseMismatchError(queryIndex int, err error) error {
+	return &CheckError{
+		Category:   ErrorCategoryDatabase,
+		Identifier: fmt.Sprintf("%d", queryIndex),
+		Err:        err,
+	}
+}
+
 func NewMockError(msg string, args ...interface{}) error {
 	return &CheckError{
 		Category: ErrorCategoryMock,
@@ -3,6 +3,8 @@ package models
 import (
 	"errors"
 	"testing"
+
+	"github.com/lamoda/gonkey/compare"
 )
 
 func TestCheckError_Error(t *testing.T) {
@@ -81,6 +83,42 @@ func TestNewStatusCodeError(t *testing.T) {
 	}
 }
 
+func TestNewStatusCodesError(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []string
+		want     string
+	}{
+		{
+			name:     "single code",
+			expected: []string{"200"},
+			want:     "status code mismatch: expected 200, got 500",
+		},
+		{
+			name:     "codes and classes",
+			expected: []string{"200", "204", "4xx"},
+			want:     "status code mismatch: expected one of 200, 204, 4xx, got 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewStatusCodesError(tt.expected, 500)
+
+			var checkErr *CheckError
+			if !errors.As(err, &checkErr) {
+				t.Fatal("expected CheckError type")
+			}
+			if checkErr.Category != ErrorCategoryStatusCode {
+				t.Errorf("Category = %v, want %v", checkErr.Category, ErrorCategoryStatusCode)
+			}
+			if checkErr.Message != tt.want {
+				t.Errorf("Message = %v, want %v", checkErr.Message, tt.want)
+			}
+		})
+	}
+}
+
 func TestNewBodyError(t *testing.T) {
 	err := NewBodyError("at path $.id: expected %d, got %d", 123, 456)
 
@@ -197,3 +235,44 @@ func TestErrorCategories(t *testing.T) {
 		}
 	}
 }
+
+func TestNewBodyMismatchError(t *testing.T) {
+	cause := &compare.MismatchError{Path: "$.id", Kind: compare.MismatchValues, Expected: 1, Actual: 2}
+	err := NewBodyMismatchError(cause)
+
+	var checkErr *CheckError
+	if !errors.As(err, &checkErr) {
+		t.Fatal("expected CheckError type")
+	}
+
+	if checkErr.Identifier != "$.id" {
+		t.Errorf("Identifier = %v, want %v", checkErr.Identifier, "$.id")
+	}
+
+	if err.Error() != cause.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), cause.Error())
+	}
+
+	mismatch, ok := checkErr.Mismatch()
+	if !ok || mismatch != cause {
+		t.Errorf("Mismatch() = %v, want %v", mismatch, cause)
+	}
+}
+
+func TestNewDatabaseMismatchError(t *testing.T) {
+	cause := &compare.MismatchError{Path: "$[0].id", Kind: compare.MismatchValues, Expected: 1, Actual: 2}
+	err := NewDatabaseMismatchError(2, cause)
+
+	var checkErr *CheckError
+	if !errors.As(err, &checkErr) {
+		t.Fatal("expected CheckError type")
+	}
+
+	if checkErr.Identifier != "2" {
+		t.Errorf("Identifier = %v, want %v", checkErr.Identifier, "2")
+	}
+
+	if _, ok := checkErr.Mismatch(); !ok {
+		t.Error("expected error to contain mismatch details")
+	}
+}
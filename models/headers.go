@@ -0,0 +1,78 @@
+package models
+
+import "strings"
+
+// AbsentHeader is expected value of a header which must not be present
+const AbsentHeader = "$absent"
+
+// HeaderValue is expected value of a header. A single value matches if any of actual values matches it,
+// a list matches if all actual values match its elements.
+type HeaderValue struct {
+	Values []string
+	IsList bool
+}
+
+// NewHeaderValue creates expectation of a single value
+func NewHeaderValue(value string) HeaderValue {
+	return HeaderValue{Values: []string{value}}
+}
+
+// NewHeaderValues creates expectation of the full list of values
+func NewHeaderValues(values ...string) HeaderValue {
+	return HeaderValue{Values: values, IsList: true}
+}
+
+func (v *HeaderValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*v = NewHeaderValue(single)
+
+		return nil
+	}
+
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*v = NewHeaderValues(list...)
+
+	return nil
+}
+
+func (v HeaderValue) MarshalYAML() (interface{}, error) {
+	if v.IsList {
+		return v.Values, nil
+	}
+
+	return v.Value(), nil
+}
+
+// Value returns the single expected value
+func (v HeaderValue) Value() string {
+	if len(v.Values) == 0 {
+		return ""
+	}
+
+	return v.Values[0]
+}
+
+// IsAbsent reports whether the header is expected to be absent
+func (v HeaderValue) IsAbsent() bool {
+	return !v.IsList && v.Value() == AbsentHeader
+}
+
+func (v HeaderValue) String() string {
+	if v.IsList {
+		return "[" + strings.Join(v.Values, ", ") + "]"
+	}
+
+	return v.Value()
+}
+
+// HeadersComparisonParams controls how response headers are compared with expected ones
+type HeadersComparisonParams struct {
+	// IgnoreValuesOrdering allows values of headers expected as lists to be in any order
+	IgnoreValuesOrdering bool `json:"ignoreValuesOrdering" yaml:"ignoreValuesOrdering"`
+	// DisallowExtraHeaders fails the check if the response has headers which are not expected
+	DisallowExtraHeaders bool `json:"disallowExtraHeaders" yaml:"disallowExtraHeaders"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestHeaderValueUnmarshalYAML(t *testing.T) {
+	var headers map[string]HeaderValue
+	err := yaml.Unmarshal([]byte(`
+Content-Type: application/json
+Content-Length: 42
+Server: $absent
+Set-Cookie:
+  - sid=abc
+  - lang=en
+`), &headers)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]HeaderValue{
+		"Content-Type":   NewHeaderValue("application/json"),
+		"Content-Length": NewHeaderValue("42"),
+		"Server":         NewHeaderValue(AbsentHeader),
+		"Set-Cookie":     NewHeaderValues("sid=abc", "lang=en"),
+	}, headers)
+
+	assert.True(t, headers["Server"].IsAbsent())
+	assert.False(t, headers["Set-Cookie"].IsAbsent())
+	assert.Equal(t, "[sid=abc, lang=en]", headers["Set-Cookie"].String())
+
+	err = yaml.Unmarshal([]byte(`Set-Cookie: {sid: abc}`), &headers)
+	assert.Error(t, err)
+}
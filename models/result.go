@@ -1,6 +1,11 @@
 package models
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/metrics"
+)
 
 type DatabaseResult struct {
 	Query    string
@@ -20,6 +25,11 @@ type Result struct {
 	Errors              []error
 	Test                TestInterface
 	DatabaseResult      []DatabaseResult
+	BodyDiff            *compare.Diff          // diff of expected and actual bodies, set when they do not match
+	Captures            map[string]interface{} // values captured by $capture in expected body, set when it matches
+	MetricsBefore       metrics.Samples        // metrics scraped before the request, set when test has metrics check
+	MetricsAfter        metrics.Samples        // metrics scraped after the request
+	Warnings            []string               // notes not failing the test, e.g. unresolved variables or snapshots which are not updated
 }
 
 func allureStatus(status string) bool {
@@ -1,5 +1,13 @@
 package models
 
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/lamoda/gonkey/compare"
+)
+
 type DatabaseCheck interface {
 	DbNameString() string
 	DbQueryString() string
@@ -9,6 +17,9 @@ type DatabaseCheck interface {
 	SetDbResponseJson([]string)
 }
 
+// AnyStatusCode is the key of variables_to_set taken from the response with any status code
+const AnyStatusCode = 0
+
 // Common Test interface
 type TestInterface interface {
 	ToQuery() string
@@ -17,8 +28,16 @@ type TestInterface interface {
 	GetMethod() string
 	Path() string
 	GetResponses() map[int]string
+	// GetResponseClasses returns responses for classes of status codes keyed by the first digit (2 for 2xx)
+	GetResponseClasses() map[int]string
 	GetResponse(code int) (string, bool)
-	GetResponseHeaders(code int) (map[string]string, bool)
+	GetResponseHeaders(code int) (map[string]HeaderValue, bool)
+	GetXPathAssertions(code int) ([]XPathAssertion, bool)
+	GetAllXPathAssertions() map[int][]XPathAssertion
+	GetBinaryAssertion(code int) (BinaryAssertion, bool)
+	GetJWTAssertions(code int) ([]JWTAssertion, bool)
+	GetMetricsCheck() *MetricsCheck
+	GetXMLNamespaces() map[string]string
 	GetName() string
 	GetDescription() string
 	GetStatus() string
@@ -39,8 +58,14 @@ type TestInterface interface {
 	DbQueryString() string
 	DbResponseJson() []string
 	GetVariables() map[string]string
+	GetCaseVariables() map[string]string
+	GetFileVariables() map[string]string
 	GetCombinedVariables() map[string]string
+	// GetVariablesToSet returns sources of variables by status codes, classes of codes keyed by the first digit
+	// and AnyStatusCode
 	GetVariablesToSet() map[int]map[string]string
+	// GetSecrets returns names of variables whose values are masked in outputs
+	GetSecrets() []string
 	GetDatabaseChecks() []DatabaseCheck
 	SetDatabaseChecks([]DatabaseCheck)
 
@@ -53,6 +78,8 @@ type TestInterface interface {
 	SetRequest(string)
 	SetForm(form *Form)
 	SetResponses(map[int]string)
+	SetResponseClasses(map[int]string)
+	SetXPathAssertions(map[int][]XPathAssertion)
 	SetHeaders(map[string]string)
 	SetDbQueryString(string)
 	SetDbResponseJson([]string)
@@ -62,6 +89,8 @@ type TestInterface interface {
 	IgnoreArraysOrdering() bool
 	DisallowExtraFields() bool
 	IgnoreDbOrdering() bool
+	GetComparisonParams() compare.Params
+	GetHeadersComparisonParams() HeadersComparisonParams
 
 	// Clone returns copy of current object
 	Clone() TestInterface
@@ -70,6 +99,78 @@ type TestInterface interface {
 	GetAllureMetadata() *AllureMetadata
 }
 
+// XPathAssertion checks result of XPath expression evaluated against XML response body
+type XPathAssertion struct {
+	// Path is XPath expression, its prefixes are resolved with xmlNamespaces of the test
+	Path string `json:"path" yaml:"path"`
+	// Value is expected value of the expression (string or list for all selected nodes), matchers can be used
+	Value interface{} `json:"value" yaml:"value"`
+	// Exists checks whether the expression selects any node, true by default
+	Exists *bool `json:"exists" yaml:"exists"`
+}
+
+// BinaryAssertion checks binary response body
+type BinaryAssertion struct {
+	// SHA256 and MD5 are expected hex encoded checksums of the body
+	SHA256 string `json:"sha256" yaml:"sha256"`
+	MD5    string `json:"md5" yaml:"md5"`
+	// Size, MinSize and MaxSize limit size of the body in bytes
+	Size    *int `json:"size" yaml:"size"`
+	MinSize *int `json:"minSize" yaml:"minSize"`
+	MaxSize *int `json:"maxSize" yaml:"maxSize"`
+	// Type is expected media type detected by the body content (image/png, application/pdf, etc.), matchers can be used
+	Type string `json:"type" yaml:"type"`
+	// Image checks properties of image (PNG, JPEG or GIF) in the body
+	Image *ImageAssertion `json:"image" yaml:"image"`
+	// File is path to the file the body must be equal to byte-for-byte
+	File string `json:"file" yaml:"file"`
+}
+
+// ImageAssertion checks properties of image
+type ImageAssertion struct {
+	// Format is expected image format: png, jpeg or gif
+	Format string `json:"format" yaml:"format"`
+	Width  *int   `json:"width" yaml:"width"`
+	Height *int   `json:"height" yaml:"height"`
+}
+
+// JWTAssertion checks JSON Web Token found in the response
+type JWTAssertion struct {
+	// Path is GJSON path to the token in JSON response body, the whole body is the token if both Path and Header are empty
+	Path string `json:"path" yaml:"path"`
+	// Header is name of the response header containing the token, "Bearer " prefix is trimmed
+	Header string `json:"header" yaml:"header"`
+	// TokenHeader and Claims are compared with decoded token, matchers can be used
+	TokenHeader map[string]interface{} `json:"tokenHeader" yaml:"tokenHeader"`
+	Claims      map[string]interface{} `json:"claims" yaml:"claims"`
+	// Verify enables signature verification
+	Verify *JWTVerification `json:"verify" yaml:"verify"`
+	// ExpiresWithin is maximum duration (1h, 30m) between now and exp claim, exp must be in the future
+	ExpiresWithin string `json:"expiresWithin" yaml:"expiresWithin"`
+	// IssuedWithin is maximum duration between iat claim and now
+	IssuedWithin string `json:"issuedWithin" yaml:"issuedWithin"`
+}
+
+// JWTVerification defines key to verify signature of JWT, only one of the fields should be set
+type JWTVerification struct {
+	// Secret is HMAC secret
+	Secret string `json:"secret" yaml:"secret"`
+	// KeyFile is path to PEM encoded public key or certificate
+	KeyFile string `json:"keyFile" yaml:"keyFile"`
+	// JWKSFile is path to JSON Web Key Set, key is selected by kid of the token
+	JWKSFile string `json:"jwksFile" yaml:"jwksFile"`
+}
+
+// MetricsCheck compares Prometheus metrics scraped before and after the request
+type MetricsCheck struct {
+	// URL of metrics endpoint, paths are relative to the host of the tested service, /metrics by default
+	URL string `json:"url" yaml:"url"`
+	// Delta maps selectors (orders_created_total{status="ok"}) to expected change of the value
+	Delta map[string]float64 `json:"delta" yaml:"delta"`
+	// Value maps selectors to expected value after the request
+	Value map[string]float64 `json:"value" yaml:"value"`
+}
+
 type Form struct {
 	Files  map[string]string `json:"files" yaml:"files"`
 	Fields map[string]string `json:"fields" yaml:"fields"`
@@ -89,3 +190,29 @@ type Fixture struct {
 }
 
 type FixturesMultiDb []Fixture
+
+// ExpectedStatusCodes returns status codes and classes of codes (2xx) expected by the test
+// in ascending order, codes go before classes
+func ExpectedStatusCodes(t TestInterface) []string {
+	codes := make([]int, 0, len(t.GetResponses()))
+	for code := range t.GetResponses() {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	classes := make([]int, 0, len(t.GetResponseClasses()))
+	for class := range t.GetResponseClasses() {
+		classes = append(classes, class)
+	}
+	sort.Ints(classes)
+
+	res := make([]string, 0, len(codes)+len(classes))
+	for _, code := range codes {
+		res = append(res, strconv.Itoa(code))
+	}
+	for _, class := range classes {
+		res = append(res, fmt.Sprintf("%dxx", class))
+	}
+
+	return res
+}
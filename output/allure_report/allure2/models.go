@@ -5,8 +5,10 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -107,6 +109,10 @@ const (
 	MimeTypeApplicationXML  = "application/xml"
 	MimeTypeImagePNG        = "image/png"
 	MimeTypeImageJPEG       = "image/jpeg"
+	MimeTypeTextDiff        = "text/x-diff"
+	MimeTypeImageGIF        = "image/gif"
+	MimeTypeApplicationPDF  = "application/pdf"
+	MimeTypeOctetStream     = "application/octet-stream"
 )
 
 func NewResult(name, targetDir string) *Result {
@@ -290,6 +296,24 @@ func generateHistoryID(name string) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// DetectMimeType returns MIME type of binary content supported by attachments,
+// unknown content is attached as application/octet-stream
+func DetectMimeType(content []byte) string {
+	mimeType := http.DetectContentType(content)
+	switch {
+	case strings.HasPrefix(mimeType, MimeTypeImagePNG):
+		return MimeTypeImagePNG
+	case strings.HasPrefix(mimeType, MimeTypeImageJPEG):
+		return MimeTypeImageJPEG
+	case strings.HasPrefix(mimeType, MimeTypeImageGIF):
+		return MimeTypeImageGIF
+	case strings.HasPrefix(mimeType, MimeTypeApplicationPDF):
+		return MimeTypeApplicationPDF
+	default:
+		return MimeTypeOctetStream
+	}
+}
+
 func getFileExtension(mimeType string) string {
 	switch mimeType {
 	case MimeTypeTextPlain:
@@ -304,6 +328,14 @@ func getFileExtension(mimeType string) string {
 		return "png"
 	case MimeTypeImageJPEG:
 		return "jpg"
+	case MimeTypeTextDiff:
+		return "diff"
+	case MimeTypeImageGIF:
+		return "gif"
+	case MimeTypeApplicationPDF:
+		return "pdf"
+	case MimeTypeOctetStream:
+		return "bin"
 	default:
 		return "txt"
 	}
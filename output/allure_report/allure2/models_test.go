@@ -203,7 +203,11 @@ func TestGetFileExtension(t *testing.T) {
 		{MimeTypeApplicationXML, "xml"},
 		{MimeTypeImagePNG, "png"},
 		{MimeTypeImageJPEG, "jpg"},
-		{"application/octet-stream", "txt"}, // default
+		{MimeTypeTextDiff, "diff"},
+		{MimeTypeImageGIF, "gif"},
+		{MimeTypeApplicationPDF, "pdf"},
+		{MimeTypeOctetStream, "bin"},
+		{"application/zip", "txt"}, // default
 	}
 
 	for _, tt := range tests {
@@ -214,6 +218,26 @@ func TestGetFileExtension(t *testing.T) {
 	}
 }
 
+func TestDetectMimeType(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"\x89PNG\r\n\x1a\n", MimeTypeImagePNG},
+		{"\xff\xd8\xff", MimeTypeImageJPEG},
+		{"GIF89a", MimeTypeImageGIF},
+		{"%PDF-1.7", MimeTypeApplicationPDF},
+		{"\x00\x01\x02", MimeTypeOctetStream},
+		{"plain text", MimeTypeOctetStream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectMimeType([]byte(tt.content)))
+		})
+	}
+}
+
 func TestGenerateHistoryID(t *testing.T) {
 	// Same name should generate same history ID
 	id1 := generateHistoryID("Test Name")
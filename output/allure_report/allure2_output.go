@@ -10,6 +10,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/metrics"
 	"github.com/lamoda/gonkey/mocks"
 	"github.com/lamoda/gonkey/models"
 	"github.com/lamoda/gonkey/output/allure_report/allure2"
@@ -96,6 +98,12 @@ func (o *Allure2Output) Process(t models.TestInterface, result *models.Result) e
 		}
 	}
 
+	if len(result.Warnings) > 0 {
+		if err := allureResult.AddAttachment("Warnings", strings.Join(result.Warnings, "\n"), allure2.MimeTypeTextPlain); err != nil {
+			return fmt.Errorf("failed to add warnings: %w", err)
+		}
+	}
+
 	o.addPreparationStep(allureResult, t)
 	if err := o.addRequestStep(allureResult, t, result); err != nil {
 		return fmt.Errorf("failed to add request step: %w", err)
@@ -195,6 +203,8 @@ func (o *Allure2Output) addVerificationSteps(result *allure2.Result, t models.Te
 		return err
 	}
 
+	o.addMetricsVerificationStep(result, t, testResult, errorCategories)
+
 	if err := o.addMockVerificationStep(result, t, errorCategories); err != nil {
 		return err
 	}
@@ -211,9 +221,10 @@ func (o *Allure2Output) addResponseVerificationStep(
 	hasStatusCodeError := len(errorCategories[models.ErrorCategoryStatusCode]) > 0
 	hasBodyError := len(errorCategories[models.ErrorCategoryResponseBody]) > 0
 	hasHeaderError := len(errorCategories[models.ErrorCategoryResponseHeader]) > 0
+	hasBinaryError := len(errorCategories[models.ErrorCategoryResponseBinary]) > 0
 
 	responseStepStatus := allure2.StatusPassed
-	if hasStatusCodeError || hasBodyError || hasHeaderError {
+	if hasStatusCodeError || hasBodyError || hasHeaderError || hasBinaryError {
 		responseStepStatus = allure2.StatusFailed
 	}
 
@@ -224,10 +235,10 @@ func (o *Allure2Output) addResponseVerificationStep(
 		statusCodeStatus = allure2.StatusFailed
 	}
 
-	expectedCodes := getExpectedStatusCodes(t.GetResponses())
+	expectedCodes := models.ExpectedStatusCodes(t)
 	statusStepName := "Проверка статус кода"
 	if len(expectedCodes) > 0 {
-		statusStepName = fmt.Sprintf("Проверка статус кода (ожидается: %s)", formatStatusCodes(expectedCodes))
+		statusStepName = fmt.Sprintf("Проверка статус кода (ожидается: %s)", strings.Join(expectedCodes, ", "))
 	}
 
 	statusStep := responseStep.StartSubStep(statusStepName)
@@ -239,15 +250,42 @@ func (o *Allure2Output) addResponseVerificationStep(
 		bodyStepStatus = allure2.StatusFailed
 	}
 
+	_, hasBinaryAssertion := t.GetBinaryAssertion(testResult.ResponseStatusCode)
+
 	bodyStep := responseStep.StartSubStep("Проверка тела ответа")
-	if testResult.ResponseBody != "" {
+	// binary bodies are attached by the binary check step
+	if testResult.ResponseBody != "" && !hasBinaryAssertion {
 		if err := bodyStep.AddAttachment("Response Body", testResult.ResponseBody,
 			allure2.MimeTypeApplicationJSON, o.reportLocation); err != nil {
 			return err
 		}
 	}
+	if testResult.BodyDiff != nil {
+		if err := bodyStep.AddAttachment("Body Diff", testResult.BodyDiff.Render(compare.DiffUnified),
+			allure2.MimeTypeTextDiff, o.reportLocation); err != nil {
+			return err
+		}
+	}
+	addMismatchSubsteps(bodyStep, errorCategories[models.ErrorCategoryResponseBody])
 	bodyStep.Finish(bodyStepStatus)
 
+	if hasBinaryAssertion {
+		binaryStepStatus := allure2.StatusPassed
+		if hasBinaryError {
+			binaryStepStatus = allure2.StatusFailed
+		}
+
+		binaryStep := responseStep.StartSubStep("Проверка бинарного тела ответа")
+		if hasBinaryError {
+			if err := binaryStep.AddAttachment("Response Binary", testResult.ResponseBody,
+				allure2.DetectMimeType([]byte(testResult.ResponseBody)), o.reportLocation); err != nil {
+				return err
+			}
+		}
+		addMismatchSubsteps(binaryStep, errorCategories[models.ErrorCategoryResponseBinary])
+		binaryStep.Finish(binaryStepStatus)
+	}
+
 	expectedHeaders, hasExpectedHeaders := t.GetResponseHeaders(testResult.ResponseStatusCode)
 	if hasExpectedHeaders && len(expectedHeaders) > 0 {
 		headerStepStatus := allure2.StatusPassed
@@ -345,6 +383,93 @@ func (o *Allure2Output) addMockVerificationStep(
 	return nil
 }
 
+func (o *Allure2Output) addMetricsVerificationStep(
+	result *allure2.Result,
+	t models.TestInterface,
+	testResult *models.Result,
+	errorCategories map[models.ErrorCategory]ErrorsByIdentifier,
+) {
+	check := t.GetMetricsCheck()
+	if check == nil {
+		return
+	}
+
+	metricsErrors := errorCategories[models.ErrorCategoryMetrics]
+	metricsStep := result.StartStep("Проверка метрик")
+
+	addSubstep := func(selector, kind string, expected float64) {
+		substepStatus := allure2.StatusPassed
+		if len(metricsErrors[selector]) > 0 {
+			substepStatus = allure2.StatusFailed
+		}
+
+		substep := metricsStep.StartSubStep(selector)
+		substep.AddParameter(kind, strconv.FormatFloat(expected, 'g', -1, 64))
+		if parsed, err := metrics.ParseSelector(selector); err == nil {
+			if value, ok := testResult.MetricsBefore.Sum(parsed); ok {
+				substep.AddParameter("before", strconv.FormatFloat(value, 'g', -1, 64))
+			}
+			if value, ok := testResult.MetricsAfter.Sum(parsed); ok {
+				substep.AddParameter("after", strconv.FormatFloat(value, 'g', -1, 64))
+			}
+		}
+		substep.Finish(substepStatus)
+	}
+
+	for _, selector := range sortedKeys(check.Delta) {
+		addSubstep(selector, "expected delta", check.Delta[selector])
+	}
+	for _, selector := range sortedKeys(check.Value) {
+		addSubstep(selector, "expected value", check.Value[selector])
+	}
+
+	metricsStepStatus := allure2.StatusPassed
+	if len(metricsErrors) > 0 {
+		metricsStepStatus = allure2.StatusFailed
+	}
+	metricsStep.Finish(metricsStepStatus)
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// addMismatchSubsteps adds a failed substep with expected and actual values for every comparison error
+func addMismatchSubsteps(step *allure2.Step, errs ErrorsByIdentifier) {
+	for _, mismatch := range collectMismatches(errs) {
+		mismatchStep := step.StartSubStep(fmt.Sprintf("%s: %s", mismatch.Path, mismatch.Kind.Message()))
+		mismatchStep.AddParameter("expected", fmt.Sprintf("%v", mismatch.Expected))
+		mismatchStep.AddParameter("actual", fmt.Sprintf("%v", mismatch.Actual))
+		mismatchStep.Finish(allure2.StatusFailed)
+	}
+}
+
+func collectMismatches(errs ErrorsByIdentifier) []*compare.MismatchError {
+	identifiers := make([]string, 0, len(errs))
+	for identifier := range errs {
+		identifiers = append(identifiers, identifier)
+	}
+	sort.Strings(identifiers)
+
+	var mismatches []*compare.MismatchError
+	for _, identifier := range identifiers {
+		for _, err := range errs[identifier] {
+			var mismatch *compare.MismatchError
+			if errors.As(err, &mismatch) {
+				mismatches = append(mismatches, mismatch)
+			}
+		}
+	}
+
+	return mismatches
+}
+
 func formatHeaders(headers map[string]string) string {
 	var lines []string
 	for k, v := range headers {
@@ -384,29 +509,6 @@ func formatDbResponse(response []string) string {
 	return fmt.Sprintf("[\n  %s\n]", strings.Join(response, ",\n  "))
 }
 
-func getExpectedStatusCodes(responses map[int]string) []int {
-	codes := make([]int, 0, len(responses))
-	for code := range responses {
-		codes = append(codes, code)
-	}
-	sort.Ints(codes)
-	return codes
-}
-
-func formatStatusCodes(codes []int) string {
-	if len(codes) == 0 {
-		return ""
-	}
-	if len(codes) == 1 {
-		return fmt.Sprintf("%d", codes[0])
-	}
-	strCodes := make([]string, len(codes))
-	for i, code := range codes {
-		strCodes[i] = fmt.Sprintf("%d", code)
-	}
-	return strings.Join(strCodes, ", ")
-}
-
 type ErrorsByIdentifier map[string][]error
 
 func categorizeErrors(errs []error) map[models.ErrorCategory]ErrorsByIdentifier {
@@ -6,8 +6,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/metrics"
 	"github.com/lamoda/gonkey/mocks"
 	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/output/allure_report/allure2"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
 )
 
 func TestGroupErrorsByEndpoint(t *testing.T) {
@@ -316,3 +320,83 @@ func TestExtractURIFromPath(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectMismatches(t *testing.T) {
+	t.Parallel()
+
+	first := &compare.MismatchError{Path: "$.a", Kind: compare.MismatchValues, Expected: 1, Actual: 2}
+	second := &compare.MismatchError{Path: "$.b", Kind: compare.MismatchKeyMissing, Expected: "c", Actual: "<missing>"}
+
+	categories := categorizeErrors([]error{
+		models.NewBodyMismatchError(second),
+		models.NewBodyErrorWithCause(errors.New("eof"), "could not parse response"),
+		models.NewBodyMismatchError(first),
+	})
+
+	mismatches := collectMismatches(categories[models.ErrorCategoryResponseBody])
+	assert.Equal(t, []*compare.MismatchError{first, second}, mismatches)
+}
+
+func TestAddResponseVerificationStep_Binary(t *testing.T) {
+	dir := t.TempDir()
+	output := NewAllure2Output(dir)
+	test := &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{
+		ResponseBinary: map[int]models.BinaryAssertion{200: {SHA256: "00"}},
+	}}
+	testResult := &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       "\x89PNG\r\n\x1a\n",
+		Errors:             []error{models.NewBinaryError("sha256 mismatch")},
+	}
+
+	result := allure2.NewResult("binary", dir)
+	err := output.addResponseVerificationStep(result, test, testResult, categorizeErrors(testResult.Errors))
+	assert.NoError(t, err)
+
+	responseStep := result.Steps[0]
+	assert.Equal(t, allure2.StatusFailed, responseStep.Status)
+
+	bodyStep := responseStep.Steps[1]
+	assert.Equal(t, allure2.StatusPassed, bodyStep.Status)
+	assert.Empty(t, bodyStep.Attachments)
+
+	binaryStep := responseStep.Steps[2]
+	assert.Equal(t, allure2.StatusFailed, binaryStep.Status)
+	if assert.Len(t, binaryStep.Attachments, 1) {
+		assert.Equal(t, allure2.MimeTypeImagePNG, binaryStep.Attachments[0].Type)
+	}
+}
+
+func TestAddMetricsVerificationStep(t *testing.T) {
+	dir := t.TempDir()
+	output := NewAllure2Output(dir)
+	test := &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{
+		MetricsCheck: &models.MetricsCheck{
+			Delta: map[string]float64{"orders_total": 1},
+			Value: map[string]float64{"queue_size": 0},
+		},
+	}}
+	testResult := &models.Result{
+		MetricsBefore: metrics.Samples{{Name: "orders_total", Value: 2}, {Name: "queue_size", Value: 1}},
+		MetricsAfter:  metrics.Samples{{Name: "orders_total", Value: 3}, {Name: "queue_size", Value: 1}},
+		Errors:        []error{models.NewMetricsError("queue_size", "expected value 0, got 1")},
+	}
+
+	result := allure2.NewResult("metrics", dir)
+	output.addMetricsVerificationStep(result, test, testResult, categorizeErrors(testResult.Errors))
+
+	metricsStep := result.Steps[0]
+	assert.Equal(t, "Проверка метрик", metricsStep.Name)
+	assert.Equal(t, allure2.StatusFailed, metricsStep.Status)
+	if assert.Len(t, metricsStep.Steps, 2) {
+		assert.Equal(t, "orders_total", metricsStep.Steps[0].Name)
+		assert.Equal(t, allure2.StatusPassed, metricsStep.Steps[0].Status)
+		assert.Equal(t, []allure2.Parameter{
+			{Name: "expected delta", Value: "1"},
+			{Name: "before", Value: "2"},
+			{Name: "after", Value: "3"},
+		}, metricsStep.Steps[0].Parameters)
+		assert.Equal(t, "queue_size", metricsStep.Steps[1].Name)
+		assert.Equal(t, allure2.StatusFailed, metricsStep.Steps[1].Status)
+	}
+}
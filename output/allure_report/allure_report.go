@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/lamoda/gonkey/compare"
 	"github.com/lamoda/gonkey/models"
 )
 
@@ -43,6 +45,18 @@ func (o *AllureReportOutput) Process(t models.TestInterface, result *models.Resu
 		*bytes.NewBufferString("Response"),
 		*bytes.NewBufferString(fmt.Sprintf(`Body: %s`, result.ResponseBody)),
 		"txt")
+	if len(result.Warnings) > 0 {
+		o.allure.AddAttachment(
+			*bytes.NewBufferString("Warnings"),
+			*bytes.NewBufferString(strings.Join(result.Warnings, "\n")),
+			"txt")
+	}
+	if result.BodyDiff != nil {
+		o.allure.AddAttachment(
+			*bytes.NewBufferString("Body Diff"),
+			*bytes.NewBufferString(result.BodyDiff.Render(compare.DiffUnified)),
+			"diff")
+	}
 
 	for i, dbresult := range result.DatabaseResult {
 		if dbresult.Query != "" {
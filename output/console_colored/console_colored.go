@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/fatih/color"
 
+	"github.com/lamoda/gonkey/compare"
 	"github.com/lamoda/gonkey/models"
 )
 
@@ -15,6 +17,7 @@ const dotsPerLine = 80
 
 type ConsoleColoredOutput struct {
 	verbose       bool
+	diffFormat    compare.DiffFormat
 	dots          int
 	coloredPrintf func(format string, a ...interface{})
 }
@@ -26,14 +29,25 @@ func NewOutput(verbose bool) *ConsoleColoredOutput {
 	}
 }
 
+// WithDiff enables diff of expected and actual bodies in given format for body mismatches
+func (o *ConsoleColoredOutput) WithDiff(format compare.DiffFormat) *ConsoleColoredOutput {
+	o.diffFormat = format
+
+	return o
+}
+
 func (o *ConsoleColoredOutput) Process(_ models.TestInterface, result *models.Result) error {
 	if !result.Passed() || o.verbose {
-		text, err := renderResult(result)
+		text, err := renderResult(result, o.diffFormat)
 		if err != nil {
 			return err
 		}
 		o.coloredPrintf("%s", text)
 	} else {
+		// warnings of passed tests are shown without the rest of the result
+		for _, warning := range result.Warnings {
+			o.coloredPrintf("\n%s %s\n", color.YellowString("Warning:"), warning)
+		}
 		o.coloredPrintf(".")
 		o.dots++
 		if o.dots%dotsPerLine == 0 {
@@ -44,7 +58,7 @@ func (o *ConsoleColoredOutput) Process(_ models.TestInterface, result *models.Re
 	return nil
 }
 
-func renderResult(result *models.Result) (string, error) {
+func renderResult(result *models.Result, diffFormat compare.DiffFormat) (string, error) {
 	text := `
        Name: {{ green .Test.GetName }}
        Description: 
@@ -85,18 +99,28 @@ Response:
 {{ end }}
 {{ end }}
 
+{{ if .Warnings }}
+Warnings:
+{{ range $w := .Warnings }}
+{{ yellow $w }}
+{{ end }}
+{{ end }}
 {{ if .Errors }}
      Result: {{ danger "ERRORS!" }}
 
 Errors:
 {{ formatErrors .Errors }}
+{{- with formatDiff .BodyDiff }}
+Diff:
+{{ . }}
+{{- end }}
 {{ else }}
      Result: {{ success "OK" }}
 {{ end }}
 `
 
 	var buffer bytes.Buffer
-	t := template.Must(template.New("letter").Funcs(templateFuncMap()).Parse(text))
+	t := template.Must(template.New("letter").Funcs(templateFuncMap(diffFormat)).Parse(text))
 	if err := t.Execute(&buffer, result); err != nil {
 		return "", err
 	}
@@ -104,7 +128,7 @@ Errors:
 	return buffer.String(), nil
 }
 
-func templateFuncMap() template.FuncMap {
+func templateFuncMap(diffFormat compare.DiffFormat) template.FuncMap {
 	return template.FuncMap{
 		"green":        color.GreenString,
 		"cyan":         color.CyanString,
@@ -113,6 +137,9 @@ func templateFuncMap() template.FuncMap {
 		"success":      color.New(color.FgHiWhite, color.BgGreen).Sprint,
 		"inc":          func(i int) int { return i + 1 },
 		"formatErrors": formatErrorsWithCategories,
+		"formatDiff": func(diff *compare.Diff) string {
+			return formatDiff(diff, diffFormat)
+		},
 	}
 }
 
@@ -137,13 +164,58 @@ func formatErrorsWithCategories(errs []error) string {
 			result += "\n"
 		}
 
-		result += fmt.Sprintf("%d) %s\n", i+1, err.Error())
+		result += fmt.Sprintf("%d) %s\n", i+1, colorizeError(err))
 		prevCategory = currentCategory
 	}
 
 	return result
 }
 
+// colorizeError highlights path, expected and actual values of comparison errors
+func colorizeError(err error) string {
+	var mismatch *compare.MismatchError
+	if !errors.As(err, &mismatch) {
+		return err.Error()
+	}
+
+	colored := fmt.Sprintf(
+		"at path %s %s:\n     expected: %s\n       actual: %s",
+		color.CyanString(mismatch.Path),
+		mismatch.Kind.Message(),
+		color.GreenString("%v", mismatch.Expected),
+		color.RedString("%v", mismatch.Actual),
+	)
+
+	return strings.Replace(err.Error(), mismatch.Error(), colored, 1)
+}
+
+// formatDiff renders colorized diff, expected lines are green and actual ones are red
+// (the same way values of comparison errors are colorized)
+func formatDiff(diff *compare.Diff, format compare.DiffFormat) string {
+	if diff == nil || format == "" {
+		return ""
+	}
+
+	result := ""
+	for _, line := range diff.Lines(format) {
+		switch line.Kind {
+		case compare.DiffLineHeader:
+			result += color.CyanString(line.Text)
+		case compare.DiffLineRemoved:
+			result += color.GreenString(line.Text)
+		case compare.DiffLineAdded:
+			result += color.RedString(line.Text)
+		case compare.DiffLineChanged:
+			result += color.YellowString(line.Text)
+		default:
+			result += line.Text
+		}
+		result += "\n"
+	}
+
+	return result
+}
+
 func (o *ConsoleColoredOutput) ShowSummary(summary *models.Summary) {
 	o.coloredPrintf(
 		"\nsuccess %d, failed %d, skipped %d, broken %d, total %d\n",
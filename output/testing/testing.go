@@ -5,18 +5,28 @@ import (
 	"fmt"
 	"text/template"
 
+	"github.com/lamoda/gonkey/compare"
 	"github.com/lamoda/gonkey/models"
 )
 
-type Output struct{}
+type Output struct {
+	diffFormat compare.DiffFormat
+}
 
 func NewOutput() *Output {
 	return &Output{}
 }
 
+// WithDiff enables diff of expected and actual bodies in given format for body mismatches
+func (o *Output) WithDiff(format compare.DiffFormat) *Output {
+	o.diffFormat = format
+
+	return o
+}
+
 func (o *Output) Process(_ models.TestInterface, result *models.Result) error {
 	if !result.Passed() {
-		text, err := renderResult(result)
+		text, err := renderResult(result, o.diffFormat)
 		if err != nil {
 			return err
 		}
@@ -26,7 +36,7 @@ func (o *Output) Process(_ models.TestInterface, result *models.Result) error {
 	return nil
 }
 
-func renderResult(result *models.Result) (string, error) {
+func renderResult(result *models.Result, diffFormat compare.DiffFormat) (string, error) {
 	text := `
        Name: {{ .Test.GetName }}
        Description:
@@ -67,6 +77,12 @@ Response:
 {{ end }}
 {{ end }}
 
+{{ if .Warnings }}
+Warnings:
+{{ range $w := .Warnings }}
+{{ $w }}
+{{ end }}
+{{ end }}
 {{ if .Errors }}
      Result: {{ "ERRORS!" }}
 
@@ -74,6 +90,10 @@ Errors:
 {{ range $i, $e := .Errors }}
 {{ inc $i }}) {{ $e.Error }}
 {{ end }}
+{{- with formatDiff .BodyDiff }}
+Diff:
+{{ . }}
+{{- end }}
 {{ else }}
      Result: {{ "OK" }}
 {{ end }}
@@ -81,6 +101,13 @@ Errors:
 
 	funcMap := template.FuncMap{
 		"inc": func(i int) int { return i + 1 },
+		"formatDiff": func(diff *compare.Diff) string {
+			if diff == nil || diffFormat == "" {
+				return ""
+			}
+
+			return diff.Render(diffFormat)
+		},
 	}
 
 	var buffer bytes.Buffer
@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestBodyDiffIsSetOnlyWithDiffFormat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id": 1, "status": "paid"}`)
+	}))
+	defer srv.Close()
+
+	for format, withDiff := range map[compare.DiffFormat]bool{"": false, compare.DiffUnified: true} {
+		var result *models.Result
+		handler := func(test models.TestInterface, executeTest testExecutor) error {
+			var err error
+			result, err = executeTest(test)
+
+			return err
+		}
+		r := New(&Config{Host: srv.URL, Variables: variables.New()}, yaml_file.NewLoader(filepath.Join("testdata", "body-diff")), handler)
+		r.AddCheckers(response_body.NewCheckerWithDiff(format))
+		require.NoError(t, r.Run())
+
+		require.NotNil(t, result)
+		assert.NotEmpty(t, result.Errors)
+		assert.Equal(t, withDiff, result.BodyDiff != nil, format)
+	}
+}
@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+type namesOutput struct {
+	names []string
+}
+
+func (o *namesOutput) Process(t models.TestInterface, _ *models.Result) error {
+	o.names = append(o.names, t.GetName())
+
+	return nil
+}
+
+func TestCasesFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"id":     strings.TrimPrefix(r.URL.Path, "/orders/"),
+			"region": r.Header.Get("X-Region"),
+		})
+	}))
+	defer srv.Close()
+
+	out := &namesOutput{}
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:     srv,
+		TestsDir:   filepath.Join("testdata", "cases-file"),
+		OutputFunc: out,
+	})
+
+	assert.Equal(t, []string{"get order #1 (european)", "get order #2 (american)"}, out.names)
+}
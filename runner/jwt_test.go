@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResponseJWT(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().Unix()
+		encode := base64.RawURLEncoding.EncodeToString
+		input := encode([]byte(`{"alg":"HS256","kid":"main"}`)) + "." + encode([]byte(fmt.Sprintf(
+			`{"sub":"42","iss":"https://auth.example.com","roles":["admin"],"iat":%d,"exp":%d}`,
+			now,
+			now+1800,
+		)))
+		mac := hmac.New(sha256.New, []byte("gonkey-secret"))
+		mac.Write([]byte(input))
+		token := input + "." + encode(mac.Sum(nil))
+
+		w.Header().Set("Authorization", "Bearer "+token)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": %q, "token_type": "Bearer"}`, token)
+	}))
+	defer srv.Close()
+
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:   srv,
+		TestsDir: filepath.Join("testdata", "jwt"),
+	})
+}
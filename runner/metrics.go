@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/lamoda/gonkey/metrics"
+	"github.com/lamoda/gonkey/models"
+)
+
+const defaultMetricsPath = "/metrics"
+
+// scrapeMetrics reads metrics of the test from Prometheus endpoint, nil is returned if the test has no metrics check
+func (r *Runner) scrapeMetrics(t models.TestInterface) (metrics.Samples, error) {
+	check := t.GetMetricsCheck()
+	if check == nil {
+		return nil, nil
+	}
+
+	url := check.URL
+	if url == "" {
+		url = defaultMetricsPath
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = r.config.Host + url
+	}
+
+	resp, err := r.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scrape metrics from %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to scrape metrics from %s: status %s", url, resp.Status)
+	}
+
+	samples, err := metrics.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse metrics from %s: %w", url, err)
+	}
+
+	return samples, nil
+}
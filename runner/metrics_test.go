@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	var created, failed int64
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			atomic.AddInt64(&failed, 1)
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+		atomic.AddInt64(&created, 1)
+		w.WriteHeader(http.StatusCreated)
+	})
+	metricsHandler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprintln(w, "# HELP orders_created_total Orders created.")
+		fmt.Fprintln(w, "# TYPE orders_created_total counter")
+		fmt.Fprintf(w, "orders_created_total{status=\"ok\"} %d\n", atomic.LoadInt64(&created))
+		fmt.Fprintf(w, "orders_created_total{status=\"failed\"} %d\n", atomic.LoadInt64(&failed))
+		fmt.Fprintln(w, "build_info{version=\"1.0\"} 1")
+	}
+	mux.HandleFunc("/metrics", metricsHandler)
+	mux.HandleFunc("/internal/metrics", metricsHandler)
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:   srv,
+		TestsDir: filepath.Join("testdata", "metrics"),
+	})
+}
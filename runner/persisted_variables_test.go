@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistedVariables(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"order": {"id": 42}}`)
+
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"order": {"id": %s}, "region": %q}`, filepath.Base(r.URL.Path), r.Header.Get("X-Region"))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "vars.json")
+
+	t.Run("bootstrap", func(t *testing.T) {
+		t.Setenv("GONKEY_VARS_OUT", path)
+		RunWithTesting(t, &RunWithTestingParams{
+			Server:   srv,
+			TestsDir: filepath.Join("testdata", "persisted-variables", "bootstrap"),
+		})
+	})
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"orderId": 42}`, string(data))
+
+	t.Run("dependent", func(t *testing.T) {
+		t.Setenv("GONKEY_VARS_IN", path)
+		t.Setenv("orderId", "1")
+		RunWithTesting(t, &RunWithTestingParams{
+			Server:           srv,
+			TestsDir:         filepath.Join("testdata", "persisted-variables", "dependent"),
+			InitialVariables: map[string]string{"region": "eu", "orderId": "2"},
+		})
+	})
+}
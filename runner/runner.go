@@ -15,6 +15,7 @@ import (
 	"github.com/lamoda/gonkey/mocks"
 	"github.com/lamoda/gonkey/models"
 	"github.com/lamoda/gonkey/output"
+	"github.com/lamoda/gonkey/template_funcs"
 	"github.com/lamoda/gonkey/testloader"
 	"github.com/lamoda/gonkey/variables"
 )
@@ -27,6 +28,14 @@ type Config struct {
 	MocksLoader           *mocks.Loader
 	Variables             *variables.Variables
 	HTTPProxyURL          *url.URL
+	// Debug prints variables visible in each test with their origins
+	Debug bool
+	// StrictVariables fails tests with variables which are not defined and have no default value,
+	// otherwise such variables are reported as warnings and left in the test as is
+	StrictVariables bool
+	// FixtureTemplates renders contents of fixture files as templates with functions of template_funcs,
+	// otherwise only variables are substituted into them
+	FixtureTemplates bool
 }
 
 type (
@@ -38,6 +47,7 @@ type Runner struct {
 	loader               testloader.LoaderInterface
 	testExecutionHandler testHandler
 	output               []output.OutputInterface
+	snapshotOutput       []output.OutputInterface
 	checkers             []checker.CheckerInterface
 	client               *http.Client
 
@@ -45,6 +55,20 @@ type Runner struct {
 }
 
 func New(config *Config, loader testloader.LoaderInterface, handler testHandler) *Runner {
+	// variables and template functions are substituted into contents of fixture files by loaders supporting it
+	if config.Variables != nil {
+		if l, ok := config.FixturesLoader.(fixtures.TemplateLoader); ok {
+			l.SetSubstitution(fixtureSubstitution(config.Variables, config.FixtureTemplates))
+		}
+		if l, ok := config.FixturesLoaderMultiDb.(fixtures.TemplateLoader); ok {
+			l.SetSubstitution(fixtureSubstitution(config.Variables, config.FixtureTemplates))
+		}
+		// values of secret variables are hidden in dumps of requests to mocks
+		if config.Mocks != nil {
+			config.Mocks.SetMask(config.Variables.Mask)
+		}
+	}
+
 	return &Runner{
 		config:               config,
 		loader:               loader,
@@ -53,10 +77,33 @@ func New(config *Config, loader testloader.LoaderInterface, handler testHandler)
 	}
 }
 
+// fixtureSubstitution renders contents of fixture file as template with functions of template_funcs if templates
+// are enabled, then substitutes variables into it. Values of variables are not parsed as templates.
+func fixtureSubstitution(vars *variables.Variables, templates bool) func(string) (string, error) {
+	return func(content string) (string, error) {
+		if templates && template_funcs.HasActions(content) {
+			var err error
+			content, err = template_funcs.Execute(content, nil)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		return vars.Perform(content), nil
+	}
+}
+
 func (r *Runner) AddOutput(o ...output.OutputInterface) {
 	r.output = append(r.output, o...)
 }
 
+// AddSnapshotOutput adds outputs writing tests and results to files (e.g. the snapshot updater),
+// values of secret variables are replaced with the variables ({{ $name }}) instead of being masked.
+// Warnings added by them to results are reported by other outputs.
+func (r *Runner) AddSnapshotOutput(o ...output.OutputInterface) {
+	r.snapshotOutput = append(r.snapshotOutput, o...)
+}
+
 func (r *Runner) AddCheckers(c ...checker.CheckerInterface) {
 	r.checkers = append(r.checkers, c...)
 }
@@ -95,8 +142,20 @@ func (r *Runner) Run() error {
 				return nil, err
 			}
 
+			if len(r.snapshotOutput) > 0 {
+				snapshotTest, snapshotResult := r.config.Variables.PlaceholderResult(test, testResult)
+				for _, o := range r.snapshotOutput {
+					if err := o.Process(snapshotTest, snapshotResult); err != nil {
+						return nil, err
+					}
+				}
+				testResult.Warnings = snapshotResult.Warnings
+			}
+
+			// outputs get copies with values of secret variables masked
+			maskedTest, maskedResult := r.config.Variables.MaskResult(test, testResult)
 			for _, o := range r.output {
-				if err := o.Process(test, testResult); err != nil {
+				if err := o.Process(maskedTest, maskedResult); err != nil {
 					return nil, err
 				}
 			}
@@ -119,9 +178,25 @@ var (
 )
 
 func (r *Runner) executeTest(v models.TestInterface) (*models.Result, error) {
-	r.config.Variables.Load(v.GetCombinedVariables())
+	r.config.Variables.EnterFile(v.GetFileName(), v.GetFileVariables())
+	r.config.Variables.EnterTest(v.GetName(), v.GetVariables(), v.GetCaseVariables())
+	r.config.Variables.MarkSecret(v.GetSecrets()...)
 	v = r.config.Variables.Apply(v)
 
+	if r.config.Debug {
+		fmt.Printf("Variables of test %s:\n%s", v.GetName(), r.config.Variables.Dump())
+	}
+
+	// warnings are reported by outputs with the result of the test
+	var warnings []string
+	if unresolved := unresolvedVariables(v); len(unresolved) > 0 {
+		err := unresolvedVariablesError(v, unresolved)
+		if r.config.StrictVariables {
+			return &models.Result{Test: v, Errors: []error{err}}, nil
+		}
+		warnings = append(warnings, err.Error())
+	}
+
 	// load fixtures
 	if r.config.FixturesLoader != nil && v.Fixtures() != nil {
 		if err := r.config.FixturesLoader.Load(v.Fixtures()); err != nil {
@@ -163,6 +238,11 @@ func (r *Runner) executeTest(v models.TestInterface) (*models.Result, error) {
 		fmt.Printf("Sleep %ds before requests\n", pause)
 	}
 
+	metricsBefore, err := r.scrapeMetrics(v)
+	if err != nil {
+		return nil, err
+	}
+
 	req, err := newRequest(r.config.Host, v)
 	if err != nil {
 		return nil, err
@@ -193,6 +273,8 @@ func (r *Runner) executeTest(v models.TestInterface) (*models.Result, error) {
 		ResponseStatus:      resp.Status,
 		ResponseHeaders:     resp.Header,
 		Test:                v,
+		MetricsBefore:       metricsBefore,
+		Warnings:            warnings,
 	}
 
 	// launch script in cmd interface
@@ -202,16 +284,19 @@ func (r *Runner) executeTest(v models.TestInterface) (*models.Result, error) {
 		}
 	}
 
+	if result.MetricsAfter, err = r.scrapeMetrics(v); err != nil {
+		return nil, err
+	}
+
 	if r.config.Mocks != nil {
 		errs := r.config.Mocks.EndRunningContext()
 		result.Errors = append(result.Errors, errs...)
 	}
 
-	if err := r.setVariablesFromResponse(v, result.ResponseContentType, bodyStr, resp.StatusCode); err != nil {
+	if err := r.setVariablesFromResponse(v, &result); err != nil {
 		return nil, err
 	}
 
-	r.config.Variables.Load(v.GetCombinedVariables())
 	v = r.config.Variables.Apply(v)
 
 	for _, c := range r.checkers {
@@ -222,31 +307,75 @@ func (r *Runner) executeTest(v models.TestInterface) (*models.Result, error) {
 		result.Errors = append(result.Errors, errs...)
 	}
 
+	if len(result.Captures) > 0 {
+		vars, err := variables.FromCaptures(result.Captures)
+		if err != nil {
+			return nil, err
+		}
+		r.config.Variables.SetFromTest(vars, fmt.Sprintf("captures of test %q", v.GetName()))
+	}
+
 	return &result, nil
 }
 
-func (r *Runner) setVariablesFromResponse(t models.TestInterface, contentType, body string, statusCode int) error {
-	varTemplates := t.GetVariablesToSet()
-	if varTemplates == nil {
-		return nil
+// unresolvedVariables returns variables left in the test after substitution,
+// variables set from the response of the test itself are substituted later and are not reported
+func unresolvedVariables(t models.TestInterface) []variables.UnresolvedVariable {
+	fromResponse := make(map[string]bool)
+	for _, templates := range t.GetVariablesToSet() {
+		for name := range templates {
+			fromResponse[strings.TrimPrefix(name, variables.GlobalPrefix)] = true
+		}
 	}
 
-	isJSON := strings.Contains(contentType, "json") && body != ""
+	var res []variables.UnresolvedVariable
+	for _, u := range variables.Unresolved(t) {
+		if !fromResponse[u.Name] {
+			res = append(res, u)
+		}
+	}
 
-	vars, err := variables.FromResponse(varTemplates[statusCode], body, isJSON)
-	if err != nil {
-		return err
+	return res
+}
+
+func unresolvedVariablesError(t models.TestInterface, unresolved []variables.UnresolvedVariable) error {
+	lines := make([]string, 0, len(unresolved))
+	for _, u := range unresolved {
+		lines = append(lines, "  "+u.String())
 	}
 
-	if vars == nil {
+	return fmt.Errorf("unresolved variables in test %q:\n%s", t.GetName(), strings.Join(lines, "\n"))
+}
+
+func (r *Runner) setVariablesFromResponse(t models.TestInterface, result *models.Result) error {
+	varTemplates := variablesToSet(t.GetVariablesToSet(), result.ResponseStatusCode)
+	if len(varTemplates) == 0 {
 		return nil
 	}
 
-	r.config.Variables.Merge(vars)
+	vars, err := variables.FromResult(varTemplates, result, t.GetXMLNamespaces())
+	if err != nil {
+		return err
+	}
+
+	r.config.Variables.SetFromTest(vars, fmt.Sprintf("variables_to_set of test %q", t.GetName()))
 
 	return nil
 }
 
+// variablesToSet returns sources of variables for the status code, sources for exact code take precedence
+// over the ones for class of codes and any code
+func variablesToSet(templates map[int]map[string]string, code int) map[string]string {
+	res := make(map[string]string)
+	for _, key := range []int{models.AnyStatusCode, code / 100, code} {
+		for name, source := range templates[key] {
+			res[name] = source
+		}
+	}
+
+	return res
+}
+
 func checkHasFocused(tests []models.TestInterface) bool {
 	for _, test := range tests {
 		if test.GetStatus() == "focus" {
@@ -10,9 +10,14 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/checker/response_binary"
 	"github.com/lamoda/gonkey/checker/response_body"
 	"github.com/lamoda/gonkey/checker/response_db"
 	"github.com/lamoda/gonkey/checker/response_header"
+	"github.com/lamoda/gonkey/checker/response_jwt"
+	"github.com/lamoda/gonkey/checker/response_metrics"
+	"github.com/lamoda/gonkey/checker/response_xpath"
+	"github.com/lamoda/gonkey/compare"
 	"github.com/lamoda/gonkey/fixtures"
 	"github.com/lamoda/gonkey/fixtures/multidb"
 	"github.com/lamoda/gonkey/mocks"
@@ -20,7 +25,6 @@ import (
 	"github.com/lamoda/gonkey/output/allure_report"
 	testingOutput "github.com/lamoda/gonkey/output/testing"
 	"github.com/lamoda/gonkey/testloader/yaml_file"
-	"github.com/lamoda/gonkey/variables"
 )
 
 type DbMapInstance struct {
@@ -39,6 +43,9 @@ type RunWithMultiDbParams struct {
 	OutputFunc    output.OutputInterface
 	Checkers      []checker.CheckerInterface
 	FixtureLoader fixtures.LoaderMultiDb
+	// InitialVariables are loaded into suite scope before the run,
+	// variables of the file given by GONKEY_VARS_IN override them
+	InitialVariables map[string]string
 }
 
 // RunWithMultiDb is a helper function the wraps the common Run and provides simple way
@@ -56,11 +63,12 @@ func RunWithMultiDb(t *testing.T, params *RunWithMultiDbParams) {
 		}
 	}
 
+	debug := os.Getenv("GONKEY_DEBUG") != ""
+
 	// Configure fixture loader
 	var fixturesLoader fixtures.LoaderMultiDb
 
 	if params.FixtureLoader == nil {
-		debug := os.Getenv("GONKEY_DEBUG") != ""
 		loaders := make(map[string]fixtures.Loader, len(params.DbMap))
 		for connName, dbInstance := range params.DbMap {
 			loaders[connName] = fixtures.NewLoader(&fixtures.Config{
@@ -94,17 +102,24 @@ func RunWithMultiDb(t *testing.T, params *RunWithMultiDbParams) {
 			Mocks:                 params.Mocks,
 			MocksLoader:           mocksLoader,
 			FixturesLoaderMultiDb: fixturesLoader,
-			Variables:             variables.New(),
+			Variables:             newVariables(t, params.InitialVariables),
 			HTTPProxyURL:          proxyURL,
+			Debug:                 debug,
+			StrictVariables:       os.Getenv("GONKEY_STRICT_VARS") != "",
+			FixtureTemplates:      os.Getenv("GONKEY_FIXTURE_TEMPLATES") != "",
 		},
 		yamlLoader,
 		handler.HandleTest,
 	)
 
+	diffFormat, err := compare.ParseDiffFormat(os.Getenv("GONKEY_DIFF"))
+	if err != nil {
+		t.Fatal(err)
+	}
 	if params.OutputFunc != nil {
 		runner.AddOutput(params.OutputFunc)
 	} else {
-		runner.AddOutput(testingOutput.NewOutput())
+		runner.AddOutput(testingOutput.NewOutput().WithDiff(diffFormat))
 	}
 
 	if os.Getenv("GONKEY_ALLURE_DIR") != "" {
@@ -113,12 +128,16 @@ func RunWithMultiDb(t *testing.T, params *RunWithMultiDbParams) {
 		runner.AddOutput(allureOutput)
 	}
 
-	runner.AddCheckers(response_body.NewChecker())
+	runner.AddCheckers(response_body.NewCheckerWithDiff(diffFormat))
 	runner.AddCheckers(response_header.NewChecker())
+	runner.AddCheckers(response_xpath.NewChecker())
+	runner.AddCheckers(response_binary.NewChecker())
+	runner.AddCheckers(response_jwt.NewChecker())
+	runner.AddCheckers(response_metrics.NewChecker())
 	runner.AddCheckers(response_db.NewMultiDbChecker(getDbConnMap(params.DbMap)))
 	runner.AddCheckers(params.Checkers...)
 
-	err := runner.Run()
+	err = runner.Run()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -14,9 +15,14 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/lamoda/gonkey/checker"
+	"github.com/lamoda/gonkey/checker/response_binary"
 	"github.com/lamoda/gonkey/checker/response_body"
 	"github.com/lamoda/gonkey/checker/response_db"
 	"github.com/lamoda/gonkey/checker/response_header"
+	"github.com/lamoda/gonkey/checker/response_jwt"
+	"github.com/lamoda/gonkey/checker/response_metrics"
+	"github.com/lamoda/gonkey/checker/response_xpath"
+	"github.com/lamoda/gonkey/compare"
 	"github.com/lamoda/gonkey/fixtures"
 	"github.com/lamoda/gonkey/mocks"
 	"github.com/lamoda/gonkey/models"
@@ -49,6 +55,44 @@ type RunWithTestingParams struct {
 	// TestIT labels: can be overridden by test-level labels
 	AllurePackage   string
 	AllureTestClass string
+	// InitialVariables are loaded into suite scope before the run,
+	// variables of the file given by GONKEY_VARS_IN override them
+	InitialVariables map[string]string
+}
+
+// newVariables creates variables with generator seed taken from GONKEY_SEED,
+// the seed is logged when the test fails, so generated values can be replayed.
+// Initial variables are loaded from the map and the file given by GONKEY_VARS_IN,
+// variables are written to the file given by GONKEY_VARS_OUT after the run.
+func newVariables(t *testing.T, initial map[string]string) *variables.Variables {
+	vars := variables.New()
+	if seed := os.Getenv("GONKEY_SEED"); seed != "" {
+		value, err := strconv.ParseInt(seed, 10, 64)
+		if err != nil {
+			t.Fatalf("invalid GONKEY_SEED: %s", err)
+		}
+		vars.SetSeed(value)
+	}
+
+	vars.Load(initial)
+	if path := os.Getenv("GONKEY_VARS_IN"); path != "" {
+		if err := vars.LoadFile(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("generated values can be replayed with GONKEY_SEED=%d", vars.Seed())
+		}
+		if path := os.Getenv("GONKEY_VARS_OUT"); path != "" {
+			if err := vars.SaveFile(path); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	return vars
 }
 
 func registerMocksEnvironment(m *mocks.Mocks) {
@@ -99,10 +143,18 @@ func RunWithTesting(t *testing.T, params *RunWithTestingParams) {
 
 	runner := initRunner(t, params, mocksLoader, fixturesLoader, proxyURL)
 
+	diffFormat, err := compare.ParseDiffFormat(os.Getenv("GONKEY_DIFF"))
+	if err != nil {
+		t.Fatal(err)
+	}
 	if params.OutputFunc != nil {
 		runner.AddOutput(params.OutputFunc)
 	} else {
-		runner.AddOutput(testingOutput.NewOutput())
+		runner.AddOutput(testingOutput.NewOutput().WithDiff(diffFormat))
+	}
+
+	if os.Getenv("GONKEY_UPDATE_SNAPSHOTS") != "" {
+		runner.AddSnapshotOutput(yaml_file.NewSnapshotUpdater())
 	}
 
 	if allureDir := os.Getenv("GONKEY_ALLURE_DIR"); allureDir != "" {
@@ -126,9 +178,9 @@ func RunWithTesting(t *testing.T, params *RunWithTestingParams) {
 		}
 	}
 
-	addCheckers(runner, params)
+	addCheckers(runner, params, diffFormat)
 
-	err := runner.Run()
+	err = runner.Run()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -147,12 +199,15 @@ func initRunner(
 	handler := testingHandler{t}
 	runner := New(
 		&Config{
-			Host:           params.Server.URL,
-			Mocks:          params.Mocks,
-			MocksLoader:    mocksLoader,
-			FixturesLoader: fixturesLoader,
-			Variables:      variables.New(),
-			HTTPProxyURL:   proxyURL,
+			Host:             params.Server.URL,
+			Mocks:            params.Mocks,
+			MocksLoader:      mocksLoader,
+			FixturesLoader:   fixturesLoader,
+			Variables:        newVariables(t, params.InitialVariables),
+			HTTPProxyURL:     proxyURL,
+			Debug:            os.Getenv("GONKEY_DEBUG") != "",
+			StrictVariables:  os.Getenv("GONKEY_STRICT_VARS") != "",
+			FixtureTemplates: os.Getenv("GONKEY_FIXTURE_TEMPLATES") != "",
 		},
 		yamlLoader,
 		handler.HandleTest,
@@ -161,9 +216,13 @@ func initRunner(
 	return runner
 }
 
-func addCheckers(runner *Runner, params *RunWithTestingParams) {
-	runner.AddCheckers(response_body.NewChecker())
+func addCheckers(runner *Runner, params *RunWithTestingParams, diffFormat compare.DiffFormat) {
+	runner.AddCheckers(response_body.NewCheckerWithDiff(diffFormat))
 	runner.AddCheckers(response_header.NewChecker())
+	runner.AddCheckers(response_xpath.NewChecker())
+	runner.AddCheckers(response_binary.NewChecker())
+	runner.AddCheckers(response_jwt.NewChecker())
+	runner.AddCheckers(response_metrics.NewChecker())
 
 	if params.DB != nil {
 		runner.AddCheckers(response_db.NewChecker(params.DB))
@@ -189,6 +248,9 @@ func (h testingHandler) HandleTest(test models.TestInterface, executeTest testEx
 			}
 		}
 
+		for _, warning := range result.Warnings {
+			t.Log("Warning: " + warning)
+		}
 		if !result.Passed() {
 			t.Fail()
 		}
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/checker/response_body"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+// recordingOutput keeps everything outputs could show
+type recordingOutput struct {
+	texts []string
+}
+
+func (o *recordingOutput) Process(t models.TestInterface, result *models.Result) error {
+	o.texts = append(o.texts,
+		fmt.Sprintf("%v %v %v", t.Headers(), t.GetRequest(), t.GetCombinedVariables()),
+		fmt.Sprintf("%v %v %v", result.Test.Headers(), result.RequestBody, result.ResponseBody),
+	)
+
+	return nil
+}
+
+func TestSecretsAreMaskedInOutputs(t *testing.T) {
+	t.Setenv("GONKEY_SECRET_PASSWORD", "env-password")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/login" {
+			_, _ = io.WriteString(w, `{"access_token": "tok-12345"}`)
+
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, `{"authorization": %q, "body": %s}`, r.Header.Get("Authorization"), body)
+	}))
+	defer srv.Close()
+
+	out := &recordingOutput{}
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:     srv,
+		TestsDir:   filepath.Join("testdata", "secrets"),
+		OutputFunc: out,
+	})
+
+	assert.Len(t, out.texts, 6)
+	for _, text := range out.texts {
+		assert.NotContains(t, text, "tok-12345")
+		assert.NotContains(t, text, "env-password")
+		assert.NotContains(t, text, "8642")
+	}
+	assert.Contains(t, out.texts[3], `"authorization": "Bearer ***"`)
+	assert.Contains(t, out.texts[4], "map[pin:***]")
+}
+
+func TestSnapshotsAreUpdatedWithSecretVariables(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "token.yaml")
+	require.NoError(t, os.WriteFile(testFile, []byte(`- name: get token
+  method: GET
+  path: /token
+  secrets:
+    - token
+  variables:
+    token: tok-12345
+  headers:
+    Authorization: Bearer {{ $token }}
+  response:
+    200: '{"token": "old"}'
+`), 0o600))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"token": "tok-12345"}`)
+	}))
+	defer srv.Close()
+
+	handler := func(test models.TestInterface, executeTest testExecutor) error {
+		_, err := executeTest(test)
+
+		return err
+	}
+	r := New(&Config{Host: srv.URL, Variables: variables.New()}, yaml_file.NewLoader(dir), handler)
+	r.AddCheckers(response_body.NewChecker())
+	r.AddSnapshotOutput(yaml_file.NewSnapshotUpdater())
+	out := &recordingOutput{}
+	r.AddOutput(out)
+	require.NoError(t, r.Run())
+
+	data, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"token": "{{ $token }}"`, "secret value is replaced with the variable")
+	assert.Equal(t, 1, strings.Count(string(data), "tok-12345"), "only the definition of the variable has the value")
+	assert.NotContains(t, string(data), "***")
+	for _, text := range out.texts {
+		assert.NotContains(t, text, "tok-12345")
+	}
+}
@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestStrictVariables(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"ok": true}`)
+	}))
+	defer srv.Close()
+
+	results := make(map[string]*models.Result)
+	handler := func(test models.TestInterface, executeTest testExecutor) error {
+		result, err := executeTest(test)
+		require.NoError(t, err)
+		results[test.GetName()] = result
+
+		return nil
+	}
+	r := New(
+		&Config{Host: srv.URL, Variables: variables.New(), StrictVariables: true},
+		yaml_file.NewLoader(filepath.Join("testdata", "strict-variables")),
+		handler,
+	)
+	require.NoError(t, r.Run())
+
+	assert.Equal(t, 1, requests, "request of the test with unresolved variables is not sent")
+
+	undefined := results["undefined variables"]
+	require.Len(t, undefined.Errors, 1)
+	assert.Equal(t,
+		"unresolved variables in test \"undefined variables\":\n  $token in headers.Authorization\n  $userId in path",
+		undefined.Errors[0].Error(),
+	)
+
+	assert.Empty(t, results["variables from the response of the test itself"].Errors)
+}
+
+func TestUnresolvedVariablesWarning(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"ok": true}`)
+	}))
+	defer srv.Close()
+
+	results := make(map[string]*models.Result)
+	handler := func(test models.TestInterface, executeTest testExecutor) error {
+		result, err := executeTest(test)
+		require.NoError(t, err)
+		results[test.GetName()] = result
+
+		return nil
+	}
+	r := New(
+		&Config{Host: srv.URL, Variables: variables.New()},
+		yaml_file.NewLoader(filepath.Join("testdata", "strict-variables")),
+		handler,
+	)
+	require.NoError(t, r.Run())
+
+	undefined := results["undefined variables"]
+	assert.Empty(t, undefined.Errors, "unresolved variables do not fail tests without strict mode")
+	assert.Equal(t,
+		[]string{"unresolved variables in test \"undefined variables\":\n  $token in headers.Authorization\n  $userId in path"},
+		undefined.Warnings,
+	)
+	assert.Empty(t, results["variables from the response of the test itself"].Warnings)
+}
@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/mocks"
+	"github.com/lamoda/gonkey/variables"
+)
+
+func TestTemplateFunctions(t *testing.T) {
+	m := mocks.NewNop("signer")
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		resp, err := http.Get(fmt.Sprintf("http://%s/?user=%s", os.Getenv("GONKEY_MOCK_SIGNER"), payload["user"]))
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+		defer resp.Body.Close()
+		var signed map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&signed)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"authorization": r.Header.Get("Authorization"),
+			"payload":       payload,
+			"signature":     signed["signature"],
+			"user":          signed["user"],
+		})
+	}))
+	defer srv.Close()
+
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:   srv,
+		TestsDir: filepath.Join("testdata", "template-funcs"),
+		Mocks:    m,
+	})
+}
+
+func TestFixtureSubstitution(t *testing.T) {
+	vs := variables.New()
+	vs.Set("name", "John")
+	vs.Set("json", `{"tmpl": "{{ .name }}", "quote": "\""}`)
+	substitute := fixtureSubstitution(vs, true)
+
+	content, err := substitute(`id: {{ "abc" | upper }}` + "\nname: {{ $name }}\ntoken: {{ $token }}")
+	require.NoError(t, err)
+	assert.Equal(t, "id: ABC\nname: John\ntoken: {{ $token }}", content)
+
+	// values of variables are substituted after rendering, so they are not parsed as templates
+	content, err = substitute(`id: {{ "abc" | upper }}` + "\ndata: {{ $json }}")
+	require.NoError(t, err)
+	assert.Equal(t, `id: ABC`+"\n"+`data: {"tmpl": "{{ .name }}", "quote": "\""}`, content)
+
+	_, err = substitute("name: {{ uper .name }}")
+	assert.ErrorContains(t, err, `function "uper" not defined`)
+
+	// without templates braces are data and only variables are substituted
+	content, err = fixtureSubstitution(vs, false)(`data: '{"greeting": "{{name}}"}'` + "\nname: {{ $name }}")
+	require.NoError(t, err)
+	assert.Equal(t, `data: '{"greeting": "{{name}}"}'`+"\nname: John", content)
+}
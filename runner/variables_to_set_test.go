@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestVariablesToSetSources(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", fmt.Sprintf("req-%d", requests))
+
+		switch r.URL.Path {
+		case "/orders":
+			w.Header().Set("Location", "/orders/42")
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "abc123"})
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"message": "order 42 is created"}`)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error": "not found"}`)
+		default:
+			var session string
+			if cookie, err := r.Cookie("sid"); err == nil {
+				session = cookie.Value
+			}
+			_, _ = fmt.Fprintf(w, `{"path": %q, "session": %q, "request": "req-1", "code": "201", "order": "42"}`, r.URL.Path, session)
+		}
+	}))
+	defer srv.Close()
+
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:   srv,
+		TestsDir: filepath.Join("testdata", "variables-to-set"),
+	})
+}
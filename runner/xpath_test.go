@@ -0,0 +1,35 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestXPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+
+		switch r.URL.Path {
+		case "/orders":
+			_, _ = w.Write([]byte(`<env:Envelope xmlns:env="http://schemas.xmlsoap.org/soap/envelope/">
+				<env:Body>
+					<CreateOrderResponse xmlns="urn:shop">
+						<OrderId>42</OrderId>
+						<Item sku="A">apple</Item>
+						<Item sku="B">pear</Item>
+					</CreateOrderResponse>
+				</env:Body>
+			</env:Envelope>`))
+		case "/orders/42":
+			_, _ = w.Write([]byte(`<shop:Order xmlns:shop="urn:shop" id="42"/>`))
+		}
+	}))
+	defer srv.Close()
+
+	RunWithTesting(t, &RunWithTestingParams{
+		Server:   srv,
+		TestsDir: filepath.Join("testdata", "xpath"),
+	})
+}
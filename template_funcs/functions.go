@@ -0,0 +1,209 @@
+package template_funcs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func builtin() template.FuncMap {
+	return template.FuncMap{
+		"json":    toJSON,
+		"toYaml":  toYAML,
+		"b64enc":  b64enc,
+		"b64dec":  b64dec,
+		"sha256":  sha256Sum,
+		"hmac":    hmacSHA256,
+		"lower":   func(v interface{}) string { return strings.ToLower(toString(v)) },
+		"upper":   func(v interface{}) string { return strings.ToUpper(toString(v)) },
+		"now":     time.Now,
+		"date":    date,
+		"dateAdd": dateAdd,
+		"default": defaultValue,
+		"indent":  indent,
+	}
+}
+
+// toJSON encodes value as JSON, maps decoded from YAML are encoded as objects
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(jsonValue(v))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			res[fmt.Sprint(key)] = jsonValue(item)
+		}
+
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			res[key] = jsonValue(item)
+		}
+
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = jsonValue(item)
+		}
+
+		return res
+	default:
+		return v
+	}
+}
+
+// toYAML encodes value as YAML without trailing newline, so it can be used with indent
+func toYAML(v interface{}) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+func b64enc(v interface{}) string {
+	return base64.StdEncoding.EncodeToString([]byte(toString(v)))
+}
+
+func b64dec(v interface{}) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(toString(v))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// sha256Sum returns hex encoded SHA-256 of value
+func sha256Sum(v interface{}) string {
+	sum := sha256.Sum256([]byte(toString(v)))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns hex encoded HMAC-SHA256 of message, key goes first to be used in pipelines: {{ .body | hmac "key" }}
+func hmacSHA256(key, message interface{}) string {
+	mac := hmac.New(sha256.New, []byte(toString(key)))
+	mac.Write([]byte(toString(message)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// date formats time with Go layout: {{ now | date "2006-01-02" }}
+func date(layout string, v interface{}) (string, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return "", err
+	}
+
+	return t.Format(layout), nil
+}
+
+// dateAdd adds duration to time: {{ now | dateAdd "-1h30m" }}, {{ now | dateAdd "7d" }}
+func dateAdd(duration string, v interface{}) (time.Time, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if days, ok := strings.CutSuffix(duration, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration %q: %w", duration, err)
+		}
+
+		return t.AddDate(0, 0, n), nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.Add(d), nil
+}
+
+// toTime converts time, RFC 3339 strings, dates (2006-01-02) and unix timestamps to time
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case int:
+		return time.Unix(int64(v), 0), nil
+	case int64:
+		return time.Unix(v, 0), nil
+	case float64:
+		return time.Unix(int64(v), 0), nil
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse(time.DateOnly, v); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("can't convert %v to time", value)
+}
+
+// defaultValue returns value, or def if value is empty: {{ .name | default "anonymous" }}
+func defaultValue(def, value interface{}) interface{} {
+	if value == nil {
+		return def
+	}
+	if v := reflect.ValueOf(value); v.IsZero() || (isCollection(v) && v.Len() == 0) {
+		return def
+	}
+
+	return value
+}
+
+func isCollection(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// indent prefixes all lines of value with n spaces
+func indent(n int, v interface{}) string {
+	pad := strings.Repeat(" ", n)
+
+	return pad + strings.ReplaceAll(toString(v), "\n", "\n"+pad)
+}
+
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
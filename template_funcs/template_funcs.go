@@ -0,0 +1,78 @@
+package template_funcs
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+const (
+	gonkeyVariableLeftPart  = "{{ $"
+	gonkeyProtectSubstitute = "!protect!"
+)
+
+// VariableTemplate matches variables of gonkey ({{ $name }}), they are not template actions
+var VariableTemplate = regexp.MustCompile(`{{\s*\$`)
+
+var (
+	mu         sync.RWMutex
+	registered = template.FuncMap{}
+)
+
+// Register adds function available in templates of case arguments, mock template replies and fixtures,
+// it replaces built-in function with the same name. Like template.Funcs, it panics if fn is not a suitable function.
+func Register(name string, fn interface{}) {
+	template.New("").Funcs(template.FuncMap{name: fn})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	registered[name] = fn
+}
+
+// FuncMap returns built-in functions with registered ones
+func FuncMap() template.FuncMap {
+	res := builtin()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for name, fn := range registered {
+		res[name] = fn
+	}
+
+	return res
+}
+
+// New creates template with functions of FuncMap
+func New(name string) *template.Template {
+	return template.New(name).Funcs(FuncMap())
+}
+
+// HasActions reports whether text contains template actions other than variables of gonkey ({{ $name }})
+func HasActions(text string) bool {
+	return strings.Contains(Protect(text), "{{")
+}
+
+// Protect replaces variables of gonkey ({{ $name }}) in text, so they are not parsed as template actions
+func Protect(text string) string {
+	return VariableTemplate.ReplaceAllString(text, gonkeyProtectSubstitute)
+}
+
+// Execute renders text as template with functions of FuncMap and given data,
+// variables of gonkey ({{ $name }}) are kept as is to be substituted later
+func Execute(text string, data interface{}) (string, error) {
+	tmpl, err := New("").Parse(Protect(text))
+	if err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(buf.String(), gonkeyProtectSubstitute, gonkeyVariableLeftPart), nil
+}
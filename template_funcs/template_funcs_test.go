@@ -0,0 +1,89 @@
+package template_funcs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute(t *testing.T) {
+	args := map[string]interface{}{
+		"name":  "John",
+		"empty": "",
+		"id":    42,
+		"user":  map[interface{}]interface{}{"name": "John", "roles": []interface{}{"admin"}},
+		"token": "dG9rZW4=",
+		"day":   "2024-02-28",
+	}
+
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{{ .user | json }}`, `{"name":"John","roles":["admin"]}`},
+		{`{{ .name | json }}`, `"John"`},
+		{`{{ .user | toYaml }}`, "name: John\nroles:\n- admin"},
+		{`{{ .user | toYaml | indent 2 }}`, "  name: John\n  roles:\n  - admin"},
+		{`{{ .name | b64enc }} {{ .token | b64dec }}`, "Sm9obg== token"},
+		{`{{ .id | sha256 }}`, "73475cb40a568e8da8a045ced110137e159f890ac4da883b6b17dc651b3a8049"},
+		{`{{ .name | hmac "key" }}`, "f25599efdc848db7c63e0fffb4b588c268f551987b7cb0a26d0bccfdee4fb79b"},
+		{`{{ .name | lower }} {{ .name | upper }}`, "john JOHN"},
+		{`{{ .day | dateAdd "2d" | date "2006-01-02" }} {{ .day | dateAdd "-36h" | date "Jan 2 15:04" }}`, "2024-03-01 Feb 26 12:00"},
+		{`{{ .empty | default "anonymous" }} {{ .name | default "anonymous" }} {{ .missing | default 1 }}`, "anonymous John 1"},
+		{`{{ .name }} {{ $token }} {{$id | json}}`, "John {{ $token }} {{ $id | json}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			got, err := Execute(tt.tmpl, args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	for _, tmpl := range []string{`{{ .token | b64dec }}`, `{{ now | dateAdd "tomorrow" }}`, `{{ .token | date "2006" }}`, `{{ unknown }}`} {
+		_, err := Execute(tmpl, map[string]interface{}{"token": "not base64!"})
+		assert.Error(t, err, tmpl)
+	}
+}
+
+func TestHasActions(t *testing.T) {
+	assert.True(t, HasActions(`{"id": {{ .id }}}`))
+	assert.False(t, HasActions(`{"id": {{ $id }}, "name": "{{$name}}"}`))
+	assert.Equal(t, `{"id": !protect!id }}}`, Protect(`{"id": {{ $id }}}`))
+}
+
+func TestNow(t *testing.T) {
+	got, err := Execute(`{{ now | date "2006-01-02" }}`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.Now().Format("2006-01-02"), got)
+}
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(registered, "reverse")
+		delete(registered, "upper")
+	})
+
+	Register("reverse", func(s string) string {
+		runes := []rune(s)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+
+		return string(runes)
+	})
+	Register("upper", func(s string) string { return strings.ToUpper(s) + "!" })
+
+	got, err := Execute(`{{ .name | reverse }} {{ .name | upper }}`, map[string]interface{}{"name": "John"})
+	require.NoError(t, err)
+	assert.Equal(t, "nhoJ JOHN!", got)
+
+	assert.Panics(t, func() { Register("invalid", "not a function") })
+}
@@ -0,0 +1,152 @@
+package yaml_file
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// casesColumns lists columns of CSV file of cases for error messages
+const casesColumns = "name, description, requestArgs.<name>, responseArgs.<code>.<name> and variables.<name>"
+
+// cases returns cases of the test definition followed by the ones loaded from casesFile
+func cases(filePath string, testDefinition TestDefinition) ([]CaseData, error) {
+	if testDefinition.CasesFile == "" {
+		return testDefinition.Cases, nil
+	}
+
+	path := relativeToTestFile(filePath, testDefinition.CasesFile)
+	loaded, err := loadCasesFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cases of test %q from %s:\n%s", testDefinition.Name, path, err)
+	}
+	if len(loaded) == 0 {
+		return nil, fmt.Errorf("cases file %s of test %q has no cases", path, testDefinition.Name)
+	}
+
+	return append(append([]CaseData{}, testDefinition.Cases...), loaded...), nil
+}
+
+// loadCasesFile reads cases from CSV file or from JSON or YAML file with the list of cases written as in test files
+func loadCasesFile(path string) ([]CaseData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return parseCasesCSV(data)
+	case ".json":
+		// keys of responseArgs are strings in JSON, encoding/json converts them to status codes,
+		// numbers are kept as written, so large ids are not rendered in exponent form
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var res []CaseData
+		if err := decoder.Decode(&res); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	case ".yaml", ".yml":
+		var res []CaseData
+		if err := yaml.Unmarshal(data, &res); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	default:
+		return nil, errors.New("unsupported format, cases are loaded from .csv, .json, .yaml and .yml files")
+	}
+}
+
+// parseCasesCSV reads cases from CSV file with header, each row is a case and columns are mapped onto its fields:
+// name, description, requestArgs.<name>, responseArgs.<code>.<name> and variables.<name>
+func parseCasesCSV(data []byte) ([]CaseData, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	setters := make([]func(*CaseData, string), len(header))
+	for i, column := range header {
+		if setters[i], err = caseColumnSetter(strings.TrimSpace(column)); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []CaseData
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var c CaseData
+		for i, value := range record {
+			setters[i](&c, value)
+		}
+		res = append(res, c)
+	}
+}
+
+func caseColumnSetter(column string) (func(*CaseData, string), error) {
+	switch column {
+	case "name":
+		return func(c *CaseData, value string) { c.Name = value }, nil
+	case "description":
+		return func(c *CaseData, value string) { c.Description = value }, nil
+	}
+
+	field, name, _ := strings.Cut(column, ".")
+	if name == "" {
+		return nil, fmt.Errorf("unknown column %q, %s are allowed", column, casesColumns)
+	}
+
+	switch field {
+	case "requestArgs":
+		return func(c *CaseData, value string) { c.RequestArgs = setArg(c.RequestArgs, name, value) }, nil
+	case "variables":
+		return func(c *CaseData, value string) { c.Variables = setArg(c.Variables, name, value) }, nil
+	case "responseArgs":
+		code, arg, _ := strings.Cut(name, ".")
+		statusCode, err := strconv.Atoi(code)
+		if err != nil || arg == "" {
+			return nil, fmt.Errorf("invalid column %q, responseArgs.<code>.<name> is expected", column)
+		}
+
+		return func(c *CaseData, value string) {
+			if c.ResponseArgs == nil {
+				c.ResponseArgs = make(map[int]map[string]interface{})
+			}
+			c.ResponseArgs[statusCode] = setArg(c.ResponseArgs[statusCode], arg, value)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown column %q, %s are allowed", column, casesColumns)
+}
+
+func setArg(args map[string]interface{}, name, value string) map[string]interface{} {
+	if args == nil {
+		args = make(map[string]interface{})
+	}
+	args[name] = value
+
+	return args
+}
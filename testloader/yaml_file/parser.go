@@ -1,45 +1,51 @@
 package yaml_file
 
 import (
-	"bytes"
 	"fmt"
 	"os"
-	"regexp"
-	"strings"
-	"text/template"
+	"path/filepath"
+	"text/template/parse"
 
 	"gopkg.in/yaml.v2"
 
 	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/template_funcs"
 )
 
-const (
-	gonkeyVariableLeftPart  = "{{ $"
-	gonkeyProtectSubstitute = "!protect!"
-)
-
-var gonkeyProtectTemplate = regexp.MustCompile(`{{\s*\$`)
-
 func parseTestDefinitionFile(absPath string) ([]Test, error) {
 	data, err := os.ReadFile(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s:\n%s", absPath, err)
 	}
 
-	var testDefinitions []TestDefinition
-
 	// reading the test source file
-	if err := yaml.Unmarshal(data, &testDefinitions); err != nil {
+	var file testFile
+	if isMappingDocument(data) {
+		err = yaml.Unmarshal(data, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file.Tests)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshall %s:\n%s", absPath, err)
 	}
+	testDefinitions := file.Tests
 
 	var tests []Test
 
 	for i := range testDefinitions {
+		if err := testDefinitions[i].ComparisonParams.Validate(); err != nil {
+			return nil, fmt.Errorf("test %q in %s has invalid comparisonParams: %s", testDefinitions[i].Name, absPath, err)
+		}
+
 		testCases, err := makeTestFromDefinition(absPath, testDefinitions[i])
 		if err != nil {
 			return nil, err
 		}
+		for j := range testCases {
+			testCases[j].definitionIndex = i
+			testCases[j].FileVariables = file.Variables
+			testCases[j].FileSecrets = file.Secrets
+		}
 
 		tests = append(tests, testCases...)
 	}
@@ -47,23 +53,110 @@ func parseTestDefinitionFile(absPath string) ([]Test, error) {
 	return tests, nil
 }
 
-func substituteArgs(tmpl string, args map[string]interface{}) (string, error) {
-	tmpl = gonkeyProtectTemplate.ReplaceAllString(tmpl, gonkeyProtectSubstitute)
+// testFile is the test file with header, tests are listed under tests key:
+//
+//	variables:
+//	  host: example.com
+//	secrets:
+//	  - token
+//	tests:
+//	  - name: ...
+//
+// Files without header are lists of tests.
+type testFile struct {
+	Variables map[string]string `yaml:"variables"`
+	Secrets   []string          `yaml:"secrets"`
+	Tests     []TestDefinition  `yaml:"tests"`
+}
 
-	compiledTmpl, err := template.New("").Parse(tmpl)
-	if err != nil {
-		return "", err
+func isMappingDocument(data []byte) bool {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+	_, ok := doc.(map[interface{}]interface{})
+
+	return ok
+}
+
+// usesArgs reports whether template refers to arguments of the case: {{ .field }}, {{ if . }}, {{ index . "field" }}
+func usesArgs(tmpl string) bool {
+	tree := parse.New("")
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(template_funcs.Protect(tmpl), "", "", map[string]*parse.Tree{}); err != nil {
+		// errors of parsing are reported by substituteArgs
+		return false
 	}
 
-	buf := &bytes.Buffer{}
+	return nodeUsesArgs(tree.Root)
+}
 
-	if err := compiledTmpl.Execute(buf, args); err != nil {
-		return "", err
+func nodeUsesArgs(node parse.Node) bool {
+	switch n := node.(type) {
+	case *parse.DotNode, *parse.FieldNode:
+		return true
+	case *parse.VariableNode:
+		return n.Ident[0] == "$"
+	case *parse.ChainNode:
+		return nodeUsesArgs(n.Node)
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if nodeUsesArgs(child) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return nodeUsesArgs(n.Pipe)
+	case *parse.TemplateNode:
+		return nodeUsesArgs(n.Pipe)
+	case *parse.IfNode:
+		return nodeUsesArgs(&n.BranchNode)
+	case *parse.RangeNode:
+		return nodeUsesArgs(&n.BranchNode)
+	case *parse.WithNode:
+		return nodeUsesArgs(&n.BranchNode)
+	case *parse.BranchNode:
+		return nodeUsesArgs(n.Pipe) || nodeUsesArgs(n.List) || nodeUsesArgs(n.ElseList)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if nodeUsesArgs(cmd) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if nodeUsesArgs(arg) {
+				return true
+			}
+		}
 	}
 
-	tmpl = strings.ReplaceAll(buf.String(), gonkeyProtectSubstitute, gonkeyVariableLeftPart)
+	return false
+}
+
+// caseVariable converts value of variable of the case to string, cases files may have numbers and booleans in them
+func caseVariable(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case map[interface{}]interface{}, map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("value %v is not a scalar", value)
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
 
-	return tmpl, nil
+// substituteArgs renders template with arguments of the case, functions of template_funcs are available in it
+func substituteArgs(tmpl string, args map[string]interface{}) (string, error) {
+	return template_funcs.Execute(tmpl, args)
 }
 
 func substituteArgsToMap(tmpl map[string]string, args map[string]interface{}) (map[string]string, error) {
@@ -79,16 +172,127 @@ func substituteArgsToMap(tmpl map[string]string, args map[string]interface{}) (m
 	return res, nil
 }
 
+func substituteArgsToHeaders(
+	tmpl map[string]models.HeaderValue,
+	args map[string]interface{},
+) (map[string]models.HeaderValue, error) {
+	res := make(map[string]models.HeaderValue, len(tmpl))
+	for key, value := range tmpl {
+		values := make([]string, len(value.Values))
+		for i, v := range value.Values {
+			var err error
+			values[i], err = substituteArgs(v, args)
+			if err != nil {
+				return nil, err
+			}
+		}
+		res[key] = models.HeaderValue{Values: values, IsList: value.IsList}
+	}
+
+	return res, nil
+}
+
+// relativeToTestFile resolves path given in the test file relative to its directory
+func relativeToTestFile(testFilePath, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(testFilePath), path)
+}
+
+// responseTemplates returns responses for exact status codes including the ones loaded from responseFile
+func responseTemplates(filePath string, testDefinition TestDefinition) (map[int]string, error) {
+	if len(testDefinition.ResponseFiles) == 0 {
+		return testDefinition.ResponseTmpls.Codes, nil
+	}
+
+	res := make(map[int]string, len(testDefinition.ResponseTmpls.Codes)+len(testDefinition.ResponseFiles))
+	for code, tpl := range testDefinition.ResponseTmpls.Codes {
+		res[code] = tpl
+	}
+	for code, path := range testDefinition.ResponseFiles {
+		if _, ok := res[code]; ok {
+			return nil, fmt.Errorf("response for status code %d is defined in both response and responseFile", code)
+		}
+
+		data, err := os.ReadFile(relativeToTestFile(filePath, path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response file for status code %d:\n%s", code, err)
+		}
+		res[code] = string(data)
+	}
+
+	return res, nil
+}
+
+// binaryAssertions returns binary assertions with files resolved relative to the test file
+func binaryAssertions(filePath string, assertions map[int]models.BinaryAssertion) map[int]models.BinaryAssertion {
+	if len(assertions) == 0 {
+		return assertions
+	}
+
+	res := make(map[int]models.BinaryAssertion, len(assertions))
+	for code, assertion := range assertions {
+		if assertion.File != "" {
+			assertion.File = relativeToTestFile(filePath, assertion.File)
+		}
+		res[code] = assertion
+	}
+
+	return res
+}
+
+// jwtAssertions returns JWT assertions with key files resolved relative to the test file
+func jwtAssertions(filePath string, assertions map[int][]models.JWTAssertion) map[int][]models.JWTAssertion {
+	if len(assertions) == 0 {
+		return assertions
+	}
+
+	res := make(map[int][]models.JWTAssertion, len(assertions))
+	for code, list := range assertions {
+		res[code] = make([]models.JWTAssertion, len(list))
+		for idx, assertion := range list {
+			if assertion.Verify != nil {
+				verify := *assertion.Verify
+				if verify.KeyFile != "" {
+					verify.KeyFile = relativeToTestFile(filePath, verify.KeyFile)
+				}
+				if verify.JWKSFile != "" {
+					verify.JWKSFile = relativeToTestFile(filePath, verify.JWKSFile)
+				}
+				assertion.Verify = &verify
+			}
+			res[code][idx] = assertion
+		}
+	}
+
+	return res
+}
+
 // Make tests from the given test definition.
 func makeTestFromDefinition(filePath string, testDefinition TestDefinition) ([]Test, error) {
 	var tests []Test
 
+	testDefinition.ResponseBinary = binaryAssertions(filePath, testDefinition.ResponseBinary)
+	testDefinition.JWTAssertions = jwtAssertions(filePath, testDefinition.JWTAssertions)
+
+	responseTmpls, err := responseTemplates(filePath, testDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	if testDefinition.Cases, err = cases(filePath, testDefinition); err != nil {
+		return nil, err
+	}
+
 	// test definition has no cases, so using request/response as is
 	if len(testDefinition.Cases) == 0 {
 		test := Test{TestDefinition: testDefinition, Filename: filePath}
 		test.Description = testDefinition.Description
 		test.Request = testDefinition.RequestTmpl
-		test.Responses = testDefinition.ResponseTmpls
+		test.Responses = responseTmpls
+		test.ResponseClasses = testDefinition.ResponseTmpls.Classes
 		test.ResponseHeaders = testDefinition.ResponseHeaders
 		test.BeforeScript = testDefinition.BeforeScriptParams.PathTmpl
 		test.AfterRequestScript = testDefinition.AfterRequestScriptParams.PathTmpl
@@ -99,9 +303,9 @@ func makeTestFromDefinition(filePath string, testDefinition TestDefinition) ([]T
 		dbChecks := []models.DatabaseCheck{}
 		for _, check := range testDefinition.DatabaseChecks {
 			dbChecks = append(dbChecks, &dbCheck{
-				dbName:   check.DbName,
-				query:    check.DbQueryTmpl,
-				response: check.DbResponseTmpl,
+				DbName:   check.DbName,
+				Query:    check.DbQueryTmpl,
+				Response: check.DbResponseTmpl,
 			})
 		}
 		test.DbChecks = dbChecks
@@ -109,8 +313,6 @@ func makeTestFromDefinition(filePath string, testDefinition TestDefinition) ([]T
 		return append(tests, test), nil
 	}
 
-	var err error
-
 	requestTmpl := testDefinition.RequestTmpl
 	beforeScriptPathTmpl := testDefinition.BeforeScriptParams.PathTmpl
 	afterRequestScriptPathTmpl := testDefinition.AfterRequestScriptParams.PathTmpl
@@ -119,11 +321,6 @@ func makeTestFromDefinition(filePath string, testDefinition TestDefinition) ([]T
 	headersValTmpl := testDefinition.HeadersVal
 	cookiesValTmpl := testDefinition.CookiesVal
 	responseHeadersTmpl := testDefinition.ResponseHeaders
-	combinedVariables := map[string]string{}
-
-	if testDefinition.Variables != nil {
-		combinedVariables = testDefinition.Variables
-	}
 
 	// produce as many tests as cases defined
 	for caseIdx, testCase := range testDefinition.Cases {
@@ -168,32 +365,55 @@ func makeTestFromDefinition(filePath string, testDefinition TestDefinition) ([]T
 
 		// substitute ResponseArgs to different parts of response
 		test.Responses = make(map[int]string)
-		for status, tpl := range testDefinition.ResponseTmpls {
-			args, ok := testCase.ResponseArgs[status]
-			if ok {
-				// found args for response status
+		for status, tpl := range responseTmpls {
+			if usesArgs(tpl) && testCase.ResponseArgs[status] == nil {
+				return nil, fmt.Errorf("response for status code %d of test %q uses arguments of cases, but case %d has no responseArgs for it",
+					status, testDefinition.Name, caseIdx+1)
+			}
+			test.Responses[status], err = substituteArgs(tpl, testCase.ResponseArgs[status])
+			if err != nil {
+				return nil, err
+			}
+		}
+		// responses for classes of codes are rendered with responseArgs of each code of the class given in the case,
+		// responses using arguments are accepted only for those codes
+		test.ResponseClasses = make(map[int]string)
+		for class, tpl := range testDefinition.ResponseTmpls.Classes {
+			rendered := false
+			for status, args := range testCase.ResponseArgs {
+				if _, exact := test.Responses[status]; status/100 != class || exact {
+					continue
+				}
 				test.Responses[status], err = substituteArgs(tpl, args)
 				if err != nil {
 					return nil, err
 				}
-			} else {
-				// not found args, using response as is
-				test.Responses[status] = tpl
+				rendered = true
+			}
+			if !usesArgs(tpl) {
+				test.ResponseClasses[class], err = substituteArgs(tpl, nil)
+				if err != nil {
+					return nil, err
+				}
+			} else if !rendered {
+				return nil, fmt.Errorf("response for status codes %dxx of test %q uses arguments of cases, "+
+					"but case %d has no responseArgs for codes of the class", class, testDefinition.Name, caseIdx+1)
 			}
 		}
 
-		test.ResponseHeaders = make(map[int]map[string]string)
+		test.ResponseHeaders = make(map[int]map[string]models.HeaderValue)
 		for status, respHeaders := range responseHeadersTmpl {
-			args, ok := testCase.ResponseArgs[status]
-			if ok {
-				// found args for response status
-				test.ResponseHeaders[status], err = substituteArgsToMap(respHeaders, args)
-				if err != nil {
-					return nil, err
+			for name, value := range respHeaders {
+				for _, v := range value.Values {
+					if usesArgs(v) && testCase.ResponseArgs[status] == nil {
+						return nil, fmt.Errorf("header %s of response for status code %d of test %q uses arguments of cases, "+
+							"but case %d has no responseArgs for it", name, status, testDefinition.Name, caseIdx+1)
+					}
 				}
-			} else {
-				// not found args, using response as is
-				test.ResponseHeaders[status] = respHeaders
+			}
+			test.ResponseHeaders[status], err = substituteArgsToHeaders(respHeaders, testCase.ResponseArgs[status])
+			if err != nil {
+				return nil, err
 			}
 		}
 
@@ -212,10 +432,20 @@ func makeTestFromDefinition(filePath string, testDefinition TestDefinition) ([]T
 			return nil, err
 		}
 
+		// variables of the case are not shared with other cases
+		test.CaseVariables = make(map[string]string, len(testCase.Variables))
+		test.CombinedVariables = make(map[string]string, len(testDefinition.Variables)+len(testCase.Variables))
+		for key, value := range testDefinition.Variables {
+			test.CombinedVariables[key] = value
+		}
 		for key, value := range testCase.Variables {
-			combinedVariables[key] = value.(string)
+			str, err := caseVariable(value)
+			if err != nil {
+				return nil, fmt.Errorf("variable %s of case %d of test %q: %w", key, caseIdx+1, testDefinition.Name, err)
+			}
+			test.CaseVariables[key] = str
+			test.CombinedVariables[key] = str
 		}
-		test.CombinedVariables = combinedVariables
 
 		// compile DbResponse
 		if testCase.DbResponse != nil {
@@ -243,14 +473,14 @@ func makeTestFromDefinition(filePath string, testDefinition TestDefinition) ([]T
 				return nil, err
 			}
 
-			c := &dbCheck{query: query}
+			c := &dbCheck{Query: query}
 			for _, tpl := range check.DbResponseTmpl {
 				responseString, err := substituteArgs(tpl, testCase.DbResponseArgs)
 				if err != nil {
 					return nil, err
 				}
 
-				c.response = append(c.response, responseString)
+				c.Response = append(c.Response, responseString)
 			}
 
 			dbChecks = append(dbChecks, c)
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 
 	"github.com/lamoda/gonkey/models"
 )
@@ -105,3 +107,170 @@ func TestParseTestsWithDbChecks(t *testing.T) {
 	assert.Equal(t, "", tests[0].GetDatabaseChecks()[0].DbNameString())
 	assert.Equal(t, "connection_name", tests[1].GetDatabaseChecks()[0].DbNameString())
 }
+
+func TestParseTestsWithComparisonParams(t *testing.T) {
+	tests, err := parseTestDefinitionFile("./testdata/with-comparison-params.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := tests[0].GetComparisonParams()
+	assert.True(t, params.DisallowExtraFields)
+	assert.Equal(t, []string{"$.meta.requestId", "$.items[*].updatedAt"}, params.IgnorePaths)
+	assert.Len(t, params.Overrides, 2)
+	assert.Equal(t, "$.items", params.Overrides[0].Path)
+	assert.True(t, *params.Overrides[0].Params.IgnoreArraysOrdering)
+	assert.Nil(t, params.Overrides[0].Params.DisallowExtraFields)
+	assert.False(t, *params.Overrides[1].Params.DisallowExtraFields)
+}
+
+func TestParseTestsWithResponseFile(t *testing.T) {
+	tests, err := parseTestDefinitionFile("./testdata/with-response-file.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.Len(t, tests, 2)
+
+	response, ok := tests[0].GetResponse(200)
+	assert.True(t, ok)
+	assert.Contains(t, response, `"id": {{ .id }},`)
+	_, ok = tests[0].GetResponse(404)
+	assert.True(t, ok)
+
+	response, ok = tests[1].GetResponse(200)
+	assert.True(t, ok)
+	assert.Contains(t, response, `"id": 2,`)
+	assert.Contains(t, response, `"customer": "{{ $customer }}"`)
+}
+
+func TestParseTestsWithResponseFileConflict(t *testing.T) {
+	_, err := parseTestDefinitionFile("./testdata/with-response-file-conflict.yaml")
+
+	assert.EqualError(t, err, "response for status code 200 is defined in both response and responseFile")
+}
+
+func TestParseTestsWithJWT(t *testing.T) {
+	tests, err := parseTestDefinitionFile("./testdata/with-jwt.yaml")
+	require.NoError(t, err)
+	require.Len(t, tests, 1)
+
+	assertions, ok := tests[0].GetJWTAssertions(200)
+	require.True(t, ok)
+	require.Len(t, assertions, 3)
+	assert.Equal(t, map[string]interface{}{"sub": "42"}, assertions[0].Claims)
+	assert.Equal(t, "testdata/keys/public.pem", assertions[0].Verify.KeyFile)
+	assert.Equal(t, "/etc/keys/jwks.json", assertions[1].Verify.JWKSFile)
+	assert.Nil(t, assertions[2].Verify)
+	assert.Equal(t, "1h", assertions[2].ExpiresWithin)
+}
+
+func TestParseTestsWithFileHeader(t *testing.T) {
+	tests, err := parseTestDefinitionFile("./testdata/with-file-header.yaml")
+	require.NoError(t, err)
+	require.Len(t, tests, 2)
+
+	for _, test := range tests {
+		assert.Equal(t, map[string]string{"userId": "42", "global:token": "secret"}, test.GetFileVariables())
+		assert.Equal(t, map[string]string{"fields": "name"}, test.GetVariables())
+		assert.Equal(t, []string{"global:token", "fields"}, test.GetSecrets())
+	}
+
+	// variables of a case are not visible in other cases
+	assert.Equal(t, map[string]string{"lang": "en"}, tests[0].GetCaseVariables())
+	assert.Equal(t, map[string]string{"region": "eu"}, tests[1].GetCaseVariables())
+	assert.Equal(t, map[string]string{"fields": "name", "region": "eu"}, tests[1].GetCombinedVariables())
+}
+
+func TestParseTestsWithCasesFile(t *testing.T) {
+	tests, err := parseTestDefinitionFile("./testdata/with-cases-file.yaml")
+	require.NoError(t, err)
+	require.Len(t, tests, 5)
+
+	assert.Equal(t, "csv #1", tests[0].GetName())
+	assert.Equal(t, "/orders/1", tests[0].Path())
+
+	assert.Equal(t, "csv #2 (paid order)", tests[1].GetName())
+	assert.Equal(t, "/orders/2", tests[1].Path())
+	response, _ := tests[1].GetResponse(200)
+	assert.Equal(t, `{"id": 2, "status": "paid", "region": "{{ $region }}"}`, response)
+	assert.Equal(t, map[string]string{"region": "eu"}, tests[1].GetCaseVariables())
+
+	assert.Equal(t, "csv #3 (shipped, partially)", tests[2].GetName())
+	response, _ = tests[2].GetResponse(200)
+	assert.Equal(t, `{"id": 3, "status": "shipped", "region": "{{ $region }}"}`, response)
+
+	assert.Equal(t, "yaml #1 (first)", tests[3].GetName())
+	response, _ = tests[3].GetResponse(200)
+	assert.Equal(t, `{"id": 4}`, response)
+	assert.Equal(t, map[string]string{"paid": "true"}, tests[3].GetCaseVariables())
+
+	assert.Equal(t, "/orders/12345678901", tests[4].Path())
+	response, _ = tests[4].GetResponse(200)
+	assert.Equal(t, `{"id": 12345678901}`, response)
+	assert.Equal(t, map[string]string{"region": "eu", "limit": "10"}, tests[4].GetCaseVariables())
+}
+
+func TestParseTestsWithCasesFileErrors(t *testing.T) {
+	_, err := parseTestDefinitionFile("./testdata/with-cases-file-errors.yaml")
+	assert.EqualError(t, err, `failed to load cases of test "unknown column" from testdata/cases/unknown-column.csv:
+unknown column "requestArg.id", name, description, requestArgs.<name>, responseArgs.<code>.<name> and variables.<name> are allowed`)
+
+	_, err = cases("./testdata/with-cases-file.yaml", TestDefinition{Name: "empty", CasesFile: "cases/empty.csv"})
+	assert.EqualError(t, err, `cases file testdata/cases/empty.csv of test "empty" has no cases`)
+
+	_, err = cases("./testdata/with-cases-file.yaml", TestDefinition{Name: "xlsx", CasesFile: "cases/orders.xlsx"})
+	assert.Error(t, err)
+
+	_, err = makeTestFromDefinition("with-cases-file.yaml", TestDefinition{
+		Name:  "list",
+		Cases: []CaseData{{Variables: map[string]interface{}{"ids": []interface{}{1, 2}}}},
+	})
+	assert.EqualError(t, err, `variable ids of case 1 of test "list": value [1 2] is not a scalar`)
+}
+
+func TestParseTestsWithCasesAndResponseClasses(t *testing.T) {
+	tests, err := parseTestDefinitionFile("./testdata/with-cases-response-classes.yaml")
+	require.NoError(t, err)
+	require.Len(t, tests, 1)
+
+	response, _ := tests[0].GetResponse(200)
+	assert.Equal(t, `{"id": 1}`, response)
+	response, _ = tests[0].GetResponse(201)
+	assert.Equal(t, `{"id": 2, "status": "new"}`, response)
+	response, _ = tests[0].GetResponse(404)
+	assert.Equal(t, `{"error": "invalid.json", "id": {{ $id }}}`, response)
+	response, _ = tests[0].GetResponse(503)
+	assert.Equal(t, `{"error": "INTERNAL"}`, response)
+
+	// response using arguments is accepted only for codes of the class with responseArgs
+	_, ok := tests[0].GetResponse(202)
+	assert.False(t, ok)
+}
+
+func TestParseTestsWithCasesAndResponseClassesErrors(t *testing.T) {
+	var definitions []TestDefinition
+	data, err := os.ReadFile("./testdata/with-cases-response-classes-errors.yaml")
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &definitions))
+	require.Len(t, definitions, 2)
+
+	_, err = makeTestFromDefinition("with-cases-response-classes-errors.yaml", definitions[0])
+	assert.EqualError(t, err, `response for status codes 2xx of test "no args for class" uses arguments of cases, `+
+		`but case 1 has no responseArgs for codes of the class`)
+
+	_, err = makeTestFromDefinition("with-cases-response-classes-errors.yaml", definitions[1])
+	assert.EqualError(t, err, `response for status code 200 of test "no args for code" uses arguments of cases, `+
+		`but case 1 has no responseArgs for it`)
+}
+
+func TestUsesArgs(t *testing.T) {
+	for _, tmpl := range []string{`{{ .id }}`, `{{ . }}`, `{{ if .ok }}yes{{ end }}`, `{{ upper .name }}`, `{{ (index . "id") }}`} {
+		assert.True(t, usesArgs(tmpl), tmpl)
+	}
+	for _, tmpl := range []string{
+		`{"id": 1}`, `{{ $id }}`, `{{ hmac "k" "a.json" }}`, `{{ "1.5" }}`, `{{ now | date "2006.01.02" }}`, `{{ unclosed`,
+	} {
+		assert.False(t, usesArgs(tmpl), tmpl)
+	}
+}
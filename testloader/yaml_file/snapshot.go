@@ -0,0 +1,220 @@
+package yaml_file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/template_funcs"
+)
+
+// SnapshotUpdater is an output which rewrites expected responses of failed tests with actual ones.
+// Responses loaded from responseFile are written to their files, other responses are replaced
+// in the test files. Matcher expressions and variables of expected JSON responses are kept.
+// Tests which can't be updated get warnings in their results.
+type SnapshotUpdater struct{}
+
+func NewSnapshotUpdater() *SnapshotUpdater {
+	return &SnapshotUpdater{}
+}
+
+func (u *SnapshotUpdater) Process(t models.TestInterface, result *models.Result) error {
+	test, ok := t.(*Test)
+	if !ok || !hasBodyErrors(result.Errors) {
+		return nil
+	}
+
+	code := result.ResponseStatusCode
+	_, inline := test.ResponseTmpls.Codes[code]
+	_, fromFile := test.ResponseFiles[code]
+	switch {
+	case len(test.Cases) > 1:
+		// responses of tests with cases are templates shared between cases
+		return skipSnapshot(result, fmt.Sprintf("responses of tests with %d cases are shared between them", len(test.Cases)))
+	case !inline && !fromFile:
+		reason := fmt.Sprintf("there is no response for status code %d", code)
+		if _, ok := test.ResponseClasses[code/100]; ok {
+			reason += fmt.Sprintf(", only for %dxx", code/100)
+		}
+
+		return skipSnapshot(result, reason)
+	}
+	expected := test.Responses[code]
+
+	body := snapshotBody(expected, result.ResponseBody, result.ResponseContentType)
+
+	if path, ok := test.ResponseFiles[code]; ok {
+		return writeFile(relativeToTestFile(test.Filename, path), []byte(body))
+	}
+
+	return updateInlineResponse(test.Filename, test.definitionIndex, code, body)
+}
+
+// skipSnapshot reports in the result of the test that the snapshot of the response is not updated
+func skipSnapshot(result *models.Result, reason string) error {
+	result.Warnings = append(result.Warnings, "snapshot of the response is not updated: "+reason)
+
+	return nil
+}
+
+func hasBodyErrors(errs []error) bool {
+	for _, err := range errs {
+		var checkErr *models.CheckError
+		if errors.As(err, &checkErr) && checkErr.GetCategory() == models.ErrorCategoryResponseBody {
+			return true
+		}
+	}
+
+	return false
+}
+
+// snapshotBody returns actual body to be stored as expected one. JSON bodies are pretty printed
+// keeping matcher expressions and variables of expected body.
+func snapshotBody(expected, actual, contentType string) string {
+	if !strings.Contains(contentType, "json") {
+		return actual
+	}
+
+	var expectedValue, actualValue interface{}
+	if err := json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		expectedValue = nil
+	}
+	if err := json.Unmarshal([]byte(actual), &actualValue); err != nil {
+		return actual
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshotValue(expectedValue, actualValue)); err != nil {
+		return actual
+	}
+
+	return buf.String()
+}
+
+// snapshotValue returns actual value where expected matcher expressions and variables are kept
+func snapshotValue(expected, actual interface{}) interface{} {
+	if s, ok := expected.(string); ok && (compare.IsMatcher(s) || template_funcs.VariableTemplate.MatchString(s)) {
+		return expected
+	}
+
+	switch actualValue := actual.(type) {
+	case map[string]interface{}:
+		expectedValue, _ := expected.(map[string]interface{})
+		res := make(map[string]interface{}, len(actualValue))
+		for k, v := range actualValue {
+			res[k] = snapshotValue(expectedValue[k], v)
+		}
+
+		return res
+	case []interface{}:
+		expectedValue, _ := expected.([]interface{})
+		res := make([]interface{}, len(actualValue))
+		for i, v := range actualValue {
+			if i < len(expectedValue) {
+				res[i] = snapshotValue(expectedValue[i], v)
+			} else {
+				res[i] = v
+			}
+		}
+
+		return res
+	default:
+		return actual
+	}
+}
+
+// updateInlineResponse replaces response for status code in the test definition with given index
+func updateInlineResponse(filename string, index, code int, body string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to unmarshall %s:\n%s", filename, err)
+	}
+	if len(doc.Content) == 0 {
+		return fmt.Errorf("test definition %d is not found in %s", index, filename)
+	}
+	// tests of files with header are listed under tests key
+	definitions := doc.Content[0]
+	if tests := mappingValue(definitions, "tests"); tests != nil {
+		definitions = tests
+	}
+	if definitions.Kind != yaml.SequenceNode || len(definitions.Content) <= index {
+		return fmt.Errorf("test definition %d is not found in %s", index, filename)
+	}
+
+	responses := mappingValue(definitions.Content[index], "response")
+	if responses == nil {
+		return fmt.Errorf("response of test definition %d is not found in %s", index, filename)
+	}
+
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		var key interface{}
+		if err := responses.Content[i].Decode(&key); err != nil {
+			return err
+		}
+		codes, _, err := parseStatusKey(key)
+		if err != nil {
+			return err
+		}
+		for _, c := range codes {
+			if c == code {
+				responses.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: body, Style: yaml.LiteralStyle}
+
+				return writeYAML(filename, &doc)
+			}
+		}
+	}
+
+	return fmt.Errorf("response for status code %d is not found in %s", code, filename)
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func writeYAML(filename string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	return writeFile(filename, buf.Bytes())
+}
+
+// writeFile overwrites existing file keeping its permissions
+func writeFile(filename string, data []byte) error {
+	stat, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, stat.Mode().Perm())
+}
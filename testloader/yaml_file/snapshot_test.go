@@ -0,0 +1,197 @@
+package yaml_file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+func TestSnapshotBody(t *testing.T) {
+	expected := `{"id": "{{ $orderId }}", "status": "$matchRegexp(^new$)", "total": 10, "items": [{"sku": "A"}]}`
+	actual := `{"id": 42, "status": "paid", "total": 12, "items": [{"sku": "B"}, {"sku": "C"}], "comment": "<none>"}`
+
+	want := `{
+  "comment": "<none>",
+  "id": "{{ $orderId }}",
+  "items": [
+    {
+      "sku": "B"
+    },
+    {
+      "sku": "C"
+    }
+  ],
+  "status": "$matchRegexp(^new$)",
+  "total": 12
+}
+`
+	assert.Equal(t, want, snapshotBody(expected, actual, "application/json"))
+	assert.Equal(t, "plain text", snapshotBody("$matchRegexp(^text$)", "plain text", "text/plain"))
+	assert.Equal(t, "not json", snapshotBody("{}", "not json", "application/json"))
+}
+
+func TestSnapshotUpdater_Process(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	responseFile := filepath.Join(dir, "order.json")
+
+	require.NoError(t, os.WriteFile(testFile, []byte(`- name: inline
+  method: GET
+  path: /status
+  response:
+    [200, 204]: |
+      {"status": "$matchRegexp(^ok$)", "version": 1}
+    404: ""
+
+- name: from file
+  method: GET
+  path: /orders/1
+  responseFile:
+    200: order.json
+`), 0o644))
+	require.NoError(t, os.WriteFile(responseFile, []byte(`{"id": 1}`), 0o644))
+
+	tests, err := parseTestDefinitionFile(testFile)
+	require.NoError(t, err)
+	require.Len(t, tests, 2)
+
+	updater := NewSnapshotUpdater()
+	bodyErr := models.NewBodyError("mismatch")
+
+	require.NoError(t, updater.Process(&tests[0], &models.Result{
+		ResponseStatusCode:  200,
+		ResponseContentType: "application/json",
+		ResponseBody:        `{"status": "ok", "version": 2}`,
+		Errors:              []error{bodyErr},
+	}))
+	require.NoError(t, updater.Process(&tests[1], &models.Result{
+		ResponseStatusCode:  200,
+		ResponseContentType: "application/json",
+		ResponseBody:        `{"id": 2}`,
+		Errors:              []error{bodyErr},
+	}))
+
+	tests, err = parseTestDefinitionFile(testFile)
+	require.NoError(t, err)
+
+	response, _ := tests[0].GetResponse(204)
+	assert.JSONEq(t, `{"status": "$matchRegexp(^ok$)", "version": 2}`, response)
+	response, _ = tests[0].GetResponse(404)
+	assert.Equal(t, "", response)
+	response, _ = tests[1].GetResponse(200)
+	assert.JSONEq(t, `{"id": 2}`, response)
+}
+
+func TestSnapshotUpdater_ProcessWithoutBodyErrors(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	content := []byte("- name: inline\n  method: GET\n  path: /status\n  response:\n    200: ok\n")
+	require.NoError(t, os.WriteFile(testFile, content, 0o644))
+
+	tests, err := parseTestDefinitionFile(testFile)
+	require.NoError(t, err)
+
+	err = NewSnapshotUpdater().Process(&tests[0], &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       "changed",
+		Errors:             []error{errors.New("mock error"), models.NewStatusCodeError(200, 500)},
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+}
+
+func TestSnapshotUpdater_ProcessFileWithHeader(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	content := "variables:\n  id: \"1\"\ntests:\n  - name: inline\n    method: GET\n    path: /status\n    response:\n      200: ok\n"
+	require.NoError(t, os.WriteFile(testFile, []byte(content), 0o644))
+
+	tests, err := parseTestDefinitionFile(testFile)
+	require.NoError(t, err)
+	require.Len(t, tests, 1)
+
+	err = NewSnapshotUpdater().Process(&tests[0], &models.Result{
+		ResponseStatusCode: 200,
+		ResponseBody:       "changed",
+		Errors:             []error{models.NewBodyError("mismatch")},
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "      200: |-\n        changed\n")
+	assert.Contains(t, string(data), "variables:\n  id: \"1\"\n")
+}
+
+func TestSnapshotUpdater_ProcessTestsWithCases(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.yaml")
+	require.NoError(t, os.WriteFile(testFile, []byte(`- name: single case
+  method: GET
+  path: /orders/{{ .id }}
+  responseFile:
+    200: order.json
+  cases:
+    - requestArgs:
+        id: 1
+      responseArgs:
+        200:
+          id: 1
+
+- name: several cases
+  method: GET
+  path: /orders/{{ .id }}
+  response:
+    200: '{"id": {{ .id }}}'
+    4xx: '{"error": "not found"}'
+  cases:
+    - requestArgs:
+        id: 1
+      responseArgs:
+        200:
+          id: 1
+    - requestArgs:
+        id: 2
+      responseArgs:
+        200:
+          id: 2
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order.json"), []byte(`{"id": {{ .id }}}`), 0o644))
+
+	tests, err := parseTestDefinitionFile(testFile)
+	require.NoError(t, err)
+	require.Len(t, tests, 3)
+
+	updater := NewSnapshotUpdater()
+	single := &models.Result{
+		ResponseStatusCode:  200,
+		ResponseContentType: "application/json",
+		ResponseBody:        `{"id": 1, "status": "new"}`,
+		Errors:              []error{models.NewBodyError("mismatch")},
+	}
+	require.NoError(t, updater.Process(&tests[0], single))
+	assert.Empty(t, single.Warnings)
+	data, err := os.ReadFile(filepath.Join(dir, "order.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": 1, "status": "new"}`, string(data))
+
+	several := &models.Result{ResponseStatusCode: 200, ResponseBody: "{}", Errors: []error{models.NewBodyError("mismatch")}}
+	require.NoError(t, updater.Process(&tests[1], several))
+	assert.Equal(t, []string{"snapshot of the response is not updated: responses of tests with 2 cases are shared between them"},
+		several.Warnings)
+
+	class := &models.Result{ResponseStatusCode: 404, ResponseBody: "{}", Errors: []error{models.NewBodyError("mismatch")}}
+	tests[2].Cases = tests[2].Cases[:1]
+	require.NoError(t, updater.Process(&tests[2], class))
+	assert.Equal(t, []string{"snapshot of the response is not updated: there is no response for status code 404, only for 4xx"},
+		class.Warnings)
+}
@@ -3,37 +3,49 @@ package yaml_file
 import (
 	"strings"
 
+	"github.com/lamoda/gonkey/compare"
 	"github.com/lamoda/gonkey/models"
 )
 
+// dbCheck fields are exported, so variables are substituted into them
 type dbCheck struct {
-	dbName   string
-	query    string
-	response []string
+	DbName   string
+	Query    string
+	Response []string
 }
 
-func (c *dbCheck) DbNameString() string         { return c.dbName }
-func (c *dbCheck) DbQueryString() string        { return c.query }
-func (c *dbCheck) DbResponseJson() []string     { return c.response }
-func (c *dbCheck) SetDbQueryString(q string)    { c.query = q }
-func (c *dbCheck) SetDbResponseJson(r []string) { c.response = r }
+func (c *dbCheck) DbNameString() string         { return c.DbName }
+func (c *dbCheck) DbQueryString() string        { return c.Query }
+func (c *dbCheck) DbResponseJson() []string     { return c.Response }
+func (c *dbCheck) SetDbQueryString(q string)    { c.Query = q }
+func (c *dbCheck) SetDbResponseJson(r []string) { c.Response = r }
 
 type Test struct {
 	TestDefinition
 
 	Filename string
 
+	// definitionIndex is the index of test definition in the file
+	definitionIndex int
+
 	Request            string
 	Responses          map[int]string
-	ResponseHeaders    map[int]map[string]string
+	ResponseClasses    map[int]string
+	ResponseHeaders    map[int]map[string]models.HeaderValue
 	BeforeScript       string
 	AfterRequestScript string
 	DbName             string
 	DbQuery            string
 	DbResponse         []string
 
+	// CaseVariables are variables of the case, FileVariables are variables of the file header
+	CaseVariables     map[string]string
+	FileVariables     map[string]string
 	CombinedVariables map[string]string
 
+	// FileSecrets are names of secret variables listed in the file header
+	FileSecrets []string
+
 	DbChecks []models.DatabaseCheck
 }
 
@@ -61,18 +73,56 @@ func (t *Test) GetResponses() map[int]string {
 	return t.Responses
 }
 
+func (t *Test) GetResponseClasses() map[int]string {
+	return t.ResponseClasses
+}
+
+// GetResponse returns expected response for status code, responses for exact codes take precedence over classes of codes
 func (t *Test) GetResponse(code int) (string, bool) {
-	val, ok := t.Responses[code]
+	if val, ok := t.Responses[code]; ok {
+		return val, true
+	}
+	val, ok := t.ResponseClasses[code/100]
 
 	return val, ok
 }
 
-func (t *Test) GetResponseHeaders(code int) (map[string]string, bool) {
+func (t *Test) GetResponseHeaders(code int) (map[string]models.HeaderValue, bool) {
 	val, ok := t.ResponseHeaders[code]
 
 	return val, ok
 }
 
+func (t *Test) GetXPathAssertions(code int) ([]models.XPathAssertion, bool) {
+	val, ok := t.XPathAssertions[code]
+
+	return val, ok
+}
+
+func (t *Test) GetAllXPathAssertions() map[int][]models.XPathAssertion {
+	return t.XPathAssertions
+}
+
+func (t *Test) GetBinaryAssertion(code int) (models.BinaryAssertion, bool) {
+	val, ok := t.ResponseBinary[code]
+
+	return val, ok
+}
+
+func (t *Test) GetJWTAssertions(code int) ([]models.JWTAssertion, bool) {
+	val, ok := t.JWTAssertions[code]
+
+	return val, ok
+}
+
+func (t *Test) GetMetricsCheck() *models.MetricsCheck {
+	return t.MetricsCheck
+}
+
+func (t *Test) GetXMLNamespaces() map[string]string {
+	return t.XMLNamespaces
+}
+
 func (t *Test) NeedsCheckingValues() bool {
 	return !t.ComparisonParams.IgnoreValues
 }
@@ -101,6 +151,14 @@ func (t *Test) IgnoreDbOrdering() bool {
 	return t.ComparisonParams.IgnoreDbOrdering
 }
 
+func (t *Test) GetComparisonParams() compare.Params {
+	return t.ComparisonParams
+}
+
+func (t *Test) GetHeadersComparisonParams() models.HeadersComparisonParams {
+	return t.HeadersComparisonParams
+}
+
 func (t *Test) Fixtures() []string {
 	return t.FixtureFiles
 }
@@ -114,7 +172,7 @@ func (t *Test) ServiceMocks() map[string]interface{} {
 }
 
 func (t *Test) Pause() int {
-	return t.PauseValue
+	return t.PauseValue.Int()
 }
 
 func (t *Test) BeforeScriptPath() string {
@@ -122,7 +180,7 @@ func (t *Test) BeforeScriptPath() string {
 }
 
 func (t *Test) BeforeScriptTimeout() int {
-	return t.BeforeScriptParams.Timeout
+	return t.BeforeScriptParams.Timeout.Int()
 }
 
 func (t *Test) AfterRequestScriptPath() string {
@@ -130,7 +188,7 @@ func (t *Test) AfterRequestScriptPath() string {
 }
 
 func (t *Test) AfterRequestScriptTimeout() int {
-	return t.AfterRequestScriptParams.Timeout
+	return t.AfterRequestScriptParams.Timeout.Int()
 }
 
 func (t *Test) Cookies() map[string]string {
@@ -165,6 +223,14 @@ func (t *Test) GetVariables() map[string]string {
 	return t.Variables
 }
 
+func (t *Test) GetCaseVariables() map[string]string {
+	return t.CaseVariables
+}
+
+func (t *Test) GetFileVariables() map[string]string {
+	return t.FileVariables
+}
+
 func (t *Test) GetCombinedVariables() map[string]string {
 	return t.CombinedVariables
 }
@@ -174,7 +240,15 @@ func (t *Test) GetForm() *models.Form {
 }
 
 func (t *Test) GetVariablesToSet() map[int]map[string]string {
-	return t.VariablesToSet
+	return t.VariablesToSet.Sources
+}
+
+func (t *Test) GetSecrets() []string {
+	secrets := make([]string, 0, len(t.FileSecrets)+len(t.Secrets)+len(t.VariablesToSet.Secrets))
+	secrets = append(secrets, t.FileSecrets...)
+	secrets = append(secrets, t.Secrets...)
+
+	return append(secrets, t.VariablesToSet.Secrets...)
 }
 
 func (t *Test) GetFileName() string {
@@ -217,6 +291,14 @@ func (t *Test) SetResponses(val map[int]string) {
 	t.Responses = val
 }
 
+func (t *Test) SetResponseClasses(val map[int]string) {
+	t.ResponseClasses = val
+}
+
+func (t *Test) SetXPathAssertions(val map[int][]models.XPathAssertion) {
+	t.XPathAssertions = val
+}
+
 func (t *Test) SetHeaders(val map[string]string) {
 	t.HeadersVal = val
 }
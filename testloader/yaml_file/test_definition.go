@@ -1,36 +1,53 @@
 package yaml_file
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
 	"github.com/lamoda/gonkey/compare"
 	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/template_funcs"
 )
 
 type TestDefinition struct {
-	Name                     string                    `json:"name" yaml:"name"`
-	Description              string                    `json:"description" yaml:"description"`
-	Status                   string                    `json:"status" yaml:"status"`
-	Variables                map[string]string         `json:"variables" yaml:"variables"`
-	VariablesToSet           VariablesToSet            `json:"variables_to_set" yaml:"variables_to_set"`
-	Form                     *models.Form              `json:"form" yaml:"form"`
-	Method                   string                    `json:"method" yaml:"method"`
-	RequestURL               string                    `json:"path" yaml:"path"`
-	QueryParams              string                    `json:"query" yaml:"query"`
-	RequestTmpl              string                    `json:"request" yaml:"request"`
-	ResponseTmpls            map[int]string            `json:"response" yaml:"response"`
-	ResponseHeaders          map[int]map[string]string `json:"responseHeaders" yaml:"responseHeaders"`
-	BeforeScriptParams       scriptParams              `json:"beforeScript" yaml:"beforeScript"`
-	AfterRequestScriptParams scriptParams              `json:"afterRequestScript" yaml:"afterRequestScript"`
-	HeadersVal               map[string]string         `json:"headers" yaml:"headers"`
-	CookiesVal               map[string]string         `json:"cookies" yaml:"cookies"`
-	Cases                    []CaseData                `json:"cases" yaml:"cases"`
-	ComparisonParams         compare.Params            `json:"comparisonParams" yaml:"comparisonParams"`
-	FixtureFiles             []string                  `json:"fixtures" yaml:"fixtures"`
-	FixturesListMultiDb      models.FixturesMultiDb    `json:"fixturesWithDb" yaml:"fixturesWithDb"`
-	MocksDefinition          map[string]interface{}    `json:"mocks" yaml:"mocks"`
-	PauseValue               int                       `json:"pause" yaml:"pause"`
-	DbQueryTmpl              string                    `json:"dbQuery" yaml:"dbQuery"`
-	DbResponseTmpl           []string                  `json:"dbResponse" yaml:"dbResponse"`
-	DatabaseChecks           []DatabaseCheck           `json:"dbChecks" yaml:"dbChecks"`
+	Name                     string                                `json:"name" yaml:"name"`
+	Description              string                                `json:"description" yaml:"description"`
+	Status                   string                                `json:"status" yaml:"status"`
+	Variables                map[string]string                     `json:"variables" yaml:"variables"`
+	VariablesToSet           VariablesToSet                        `json:"variables_to_set" yaml:"variables_to_set"`
+	Secrets                  []string                              `json:"secrets" yaml:"secrets"`
+	Form                     *models.Form                          `json:"form" yaml:"form"`
+	Method                   string                                `json:"method" yaml:"method"`
+	RequestURL               string                                `json:"path" yaml:"path"`
+	QueryParams              string                                `json:"query" yaml:"query"`
+	RequestTmpl              string                                `json:"request" yaml:"request"`
+	ResponseTmpls            ResponseTemplates                     `json:"response" yaml:"response"`
+	ResponseFiles            map[int]string                        `json:"responseFile" yaml:"responseFile"`
+	ResponseHeaders          map[int]map[string]models.HeaderValue `json:"responseHeaders" yaml:"responseHeaders"`
+	XPathAssertions          map[int][]models.XPathAssertion       `json:"xpathAssertions" yaml:"xpathAssertions"`
+	XMLNamespaces            map[string]string                     `json:"xmlNamespaces" yaml:"xmlNamespaces"`
+	ResponseBinary           map[int]models.BinaryAssertion        `json:"responseBinary" yaml:"responseBinary"`
+	JWTAssertions            map[int][]models.JWTAssertion         `json:"jwt" yaml:"jwt"`
+	MetricsCheck             *models.MetricsCheck                  `json:"metrics" yaml:"metrics"`
+	BeforeScriptParams       scriptParams                          `json:"beforeScript" yaml:"beforeScript"`
+	AfterRequestScriptParams scriptParams                          `json:"afterRequestScript" yaml:"afterRequestScript"`
+	HeadersVal               map[string]string                     `json:"headers" yaml:"headers"`
+	CookiesVal               map[string]string                     `json:"cookies" yaml:"cookies"`
+	Cases                    []CaseData                            `json:"cases" yaml:"cases"`
+	CasesFile                string                                `json:"casesFile" yaml:"casesFile"`
+	ComparisonParams         compare.Params                        `json:"comparisonParams" yaml:"comparisonParams"`
+	HeadersComparisonParams  models.HeadersComparisonParams        `json:"headersComparisonParams" yaml:"headersComparisonParams"`
+	FixtureFiles             []string                              `json:"fixtures" yaml:"fixtures"`
+	FixturesListMultiDb      models.FixturesMultiDb                `json:"fixturesWithDb" yaml:"fixturesWithDb"`
+	MocksDefinition          map[string]interface{}                `json:"mocks" yaml:"mocks"`
+	PauseValue               intValue                              `json:"pause" yaml:"pause"`
+	DbQueryTmpl              string                                `json:"dbQuery" yaml:"dbQuery"`
+	DbResponseTmpl           []string                              `json:"dbResponse" yaml:"dbResponse"`
+	DatabaseChecks           []DatabaseCheck                       `json:"dbChecks" yaml:"dbChecks"`
 
 	// Allure metadata (for TMS integration: TestIT, Allure TestOps, etc.)
 	Allure *models.AllureMetadata `json:"allure" yaml:"allure"`
@@ -56,19 +73,56 @@ type DatabaseCheck struct {
 }
 
 type scriptParams struct {
-	PathTmpl string `json:"path" yaml:"path"`
-	Timeout  int    `json:"timeout" yaml:"timeout"`
+	PathTmpl string   `json:"path" yaml:"path"`
+	Timeout  intValue `json:"timeout" yaml:"timeout"`
 }
 
-type VariablesToSet map[int]map[string]string
+// intValue is an integer which can be given with variables: pause: "{{ $delay }}"
+type intValue string
+
+func (v *intValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value interface{}
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+
+	switch value := value.(type) {
+	case nil:
+		*v = ""
+	case int:
+		*v = intValue(strconv.Itoa(value))
+	case string:
+		if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil && !template_funcs.VariableTemplate.MatchString(value) {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		*v = intValue(value)
+	default:
+		return fmt.Errorf("invalid integer %v", value)
+	}
+
+	return nil
+}
+
+// Int returns the integer, zero is returned for empty value or value with unknown variables
+func (v intValue) Int() int {
+	n, _ := strconv.Atoi(strings.TrimSpace(string(v)))
+
+	return n
+}
+
+// VariablesToSet are sources of variables by status codes and names of variables marked as secret
+type VariablesToSet struct {
+	Sources map[int]map[string]string
+	Secrets []string
+}
 
 /*
 There can be two types of data in yaml-file:
- 1. JSON-paths:
+ 1. Sources of values:
     VariablesToSet:
     <code1>:
     <varName1>: <JSON_Path1>
-    <varName2>: <JSON_Path2>
+    <varName2>: header:<Header>
  2. Plain text:
     VariablesToSet:
     <code1>: <varName1>
@@ -80,31 +134,200 @@ There can be two types of data in yaml-file:
     <varName1>: ""
     <code2>:
     <varName2>: ""
+ 3. Sources of secret values, their values are masked in outputs:
+    VariablesToSet:
+    <code1>:
+    <varName1>:
+    source: <JSON_Path1>
+    secret: true
+
+Keys are status codes, classes of codes (2xx) keyed by the first digit, lists of them or any
+keyed by models.AnyStatusCode.
 */
 func (v *VariablesToSet) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	res := make(map[int]map[string]string)
+	var items yaml.MapSlice
+	if err := unmarshal(&items); err != nil {
+		return err
+	}
 
-	// try to unmarshall as plaint text
-	var plain map[int]string
-	if err := unmarshal(&plain); err == nil {
+	res := VariablesToSet{Sources: make(map[int]map[string]string)}
+	for _, item := range items {
+		vars, secrets, err := variablesToSetValue(item)
+		if err != nil {
+			return err
+		}
+		res.Secrets = append(res.Secrets, secrets...)
 
-		for code, varName := range plain {
-			res[code] = map[string]string{
-				varName: "",
+		var keys []int
+		if key, ok := item.Key.(string); ok && key == "any" {
+			keys = []int{models.AnyStatusCode}
+		} else {
+			codes, classes, err := parseStatusKey(item.Key)
+			if err != nil {
+				return err
 			}
+			keys = append(codes, classes...)
 		}
 
-		*v = res
+		for _, key := range keys {
+			if res.Sources[key] == nil {
+				res.Sources[key] = make(map[string]string, len(vars))
+			}
+			for name, source := range vars {
+				res.Sources[key][name] = source
+			}
+		}
+	}
+
+	*v = res
+
+	return nil
+}
+
+// variablesToSetValue returns sources of variables by names and names of secret variables,
+// plain text value is the name of variable for the whole body
+func variablesToSetValue(item yaml.MapItem) (map[string]string, []string, error) {
+	switch value := item.Value.(type) {
+	case string:
+		return map[string]string{value: ""}, nil, nil
+	case yaml.MapSlice:
+		res := make(map[string]string, len(value))
+		var secrets []string
+		for _, v := range value {
+			name := fmt.Sprint(v.Key)
+			switch source := v.Value.(type) {
+			case nil:
+				res[name] = ""
+			case string:
+				res[name] = source
+			case yaml.MapSlice:
+				var secret bool
+				var err error
+				if res[name], secret, err = secretSource(source); err != nil {
+					return nil, nil, fmt.Errorf("variable %s for status %v: %w", name, item.Key, err)
+				}
+				if secret {
+					secrets = append(secrets, name)
+				}
+			default:
+				return nil, nil, fmt.Errorf("source of variable %v for status %v must be a string", v.Key, item.Key)
+			}
+		}
 
-		return nil
+		return res, secrets, nil
+	default:
+		return nil, nil, fmt.Errorf("variables_to_set for status %v must be a name or a map of names to sources", item.Key)
 	}
+}
+
+// secretSource parses source given as map: {source: access_token, secret: true}
+func secretSource(value yaml.MapSlice) (string, bool, error) {
+	var source string
+	var secret bool
+	for _, item := range value {
+		var ok bool
+		switch item.Key {
+		case "source":
+			source, ok = item.Value.(string)
+		case "secret":
+			secret, ok = item.Value.(bool)
+		default:
+			return "", false, fmt.Errorf("unknown key %v, source and secret are allowed", item.Key)
+		}
+		if !ok {
+			return "", false, fmt.Errorf("invalid %v: %v", item.Key, item.Value)
+		}
+	}
+
+	return source, secret, nil
+}
+
+// ResponseTemplates are expected responses by status code. Besides single codes, keys can be
+// classes of codes (2xx) and lists of codes ([200, 204] or "200, 204") sharing the same response.
+type ResponseTemplates struct {
+	// Codes contains responses for exact status codes, lists of codes are expanded into them
+	Codes map[int]string
+	// Classes contains responses for classes of status codes keyed by the first digit (2 for 2xx)
+	Classes map[int]string
+}
 
-	// json-paths
-	if err := unmarshal(&res); err != nil {
+var statusClassRegexp = regexp.MustCompile(`^([1-5])[xX]{2}$`)
+
+func (r *ResponseTemplates) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var items yaml.MapSlice
+	if err := unmarshal(&items); err != nil {
 		return err
 	}
 
-	*v = res
+	res := ResponseTemplates{Codes: map[int]string{}, Classes: map[int]string{}}
+	for _, item := range items {
+		var tpl string
+		switch v := item.Value.(type) {
+		case nil:
+		case string:
+			tpl = v
+		case int, float64, bool:
+			tpl = fmt.Sprint(v)
+		default:
+			return fmt.Errorf("response for status %v must be a string", item.Key)
+		}
+
+		codes, classes, err := parseStatusKey(item.Key)
+		if err != nil {
+			return err
+		}
+		for _, code := range codes {
+			if _, ok := res.Codes[code]; ok {
+				return fmt.Errorf("response for status code %d is defined more than once", code)
+			}
+			res.Codes[code] = tpl
+		}
+		for _, class := range classes {
+			if _, ok := res.Classes[class]; ok {
+				return fmt.Errorf("response for status codes %dxx is defined more than once", class)
+			}
+			res.Classes[class] = tpl
+		}
+	}
+
+	*r = res
 
 	return nil
 }
+
+// parseStatusKey parses key of response: a status code, a class of codes (2xx) or a list of them
+func parseStatusKey(key interface{}) (codes, classes []int, err error) {
+	var parts []interface{}
+	switch v := key.(type) {
+	case []interface{}:
+		parts = v
+	case string:
+		for _, part := range strings.Split(v, ",") {
+			parts = append(parts, strings.TrimSpace(part))
+		}
+	default:
+		parts = []interface{}{v}
+	}
+
+	for _, part := range parts {
+		if s, ok := part.(string); ok {
+			if m := statusClassRegexp.FindStringSubmatch(s); m != nil {
+				class, _ := strconv.Atoi(m[1])
+				classes = append(classes, class)
+
+				continue
+			}
+			if code, err := strconv.Atoi(s); err == nil {
+				part = code
+			}
+		}
+
+		code, ok := part.(int)
+		if !ok || code < 100 || code > 599 {
+			return nil, nil, fmt.Errorf("invalid status code %v in response key %v", part, key)
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, classes, nil
+}
@@ -0,0 +1,165 @@
+package yaml_file
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+func TestResponseTemplates_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantCodes   map[int]string
+		wantClasses map[int]string
+		wantErr     string
+	}{
+		{
+			name:        "single codes",
+			data:        "200: ok\n404: not found",
+			wantCodes:   map[int]string{200: "ok", 404: "not found"},
+			wantClasses: map[int]string{},
+		},
+		{
+			name:        "class of codes",
+			data:        "2xx: ok\n5XX: error",
+			wantCodes:   map[int]string{},
+			wantClasses: map[int]string{2: "ok", 5: "error"},
+		},
+		{
+			name:        "list of codes",
+			data:        "[200, 204]: ok\n\"404, 410\": gone",
+			wantCodes:   map[int]string{200: "ok", 204: "ok", 404: "gone", 410: "gone"},
+			wantClasses: map[int]string{},
+		},
+		{
+			name:        "list of codes and classes",
+			data:        "[200, 3xx]: ok",
+			wantCodes:   map[int]string{200: "ok"},
+			wantClasses: map[int]string{3: "ok"},
+		},
+		{
+			name:    "code defined twice",
+			data:    "200: ok\n[200, 204]: ok",
+			wantErr: "response for status code 200 is defined more than once",
+		},
+		{
+			name:    "invalid code",
+			data:    "2x: ok",
+			wantErr: "invalid status code 2x in response key 2x",
+		},
+		{
+			name:    "code out of range",
+			data:    "[200, 600]: ok",
+			wantErr: "invalid status code 600 in response key [200 600]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res ResponseTemplates
+			err := yaml.Unmarshal([]byte(tt.data), &res)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCodes, res.Codes)
+			assert.Equal(t, tt.wantClasses, res.Classes)
+		})
+	}
+}
+
+func TestTest_GetResponse(t *testing.T) {
+	test := Test{
+		Responses:       map[int]string{404: "not found"},
+		ResponseClasses: map[int]string{2: "ok", 4: "error"},
+	}
+
+	for code, want := range map[int]string{201: "ok", 404: "not found", 410: "error"} {
+		got, ok := test.GetResponse(code)
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+
+	_, ok := test.GetResponse(500)
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"404", "2xx", "4xx"}, models.ExpectedStatusCodes(&test))
+}
+
+func TestVariablesToSet_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    VariablesToSet
+		wantErr string
+	}{
+		{
+			name: "plain text",
+			data: "200: id",
+			want: VariablesToSet{Sources: map[int]map[string]string{200: {"id": ""}}},
+		},
+		{
+			name: "sources",
+			data: "201:\n  id: id\n  location: header:Location\n  sid: cookie:sid",
+			want: VariablesToSet{Sources: map[int]map[string]string{
+				201: {"id": "id", "location": "header:Location", "sid": "cookie:sid"},
+			}},
+		},
+		{
+			name: "any code, classes and lists",
+			data: "any:\n  code: \"status:\"\n2xx: body\n[400, 404]:\n  error: error.message",
+			want: VariablesToSet{Sources: map[int]map[string]string{
+				models.AnyStatusCode: {"code": "status:"},
+				2:                    {"body": ""},
+				400:                  {"error": "error.message"},
+				404:                  {"error": "error.message"},
+			}},
+		},
+		{
+			name: "secret",
+			data: "200:\n  token:\n    source: header:Authorization\n    secret: true\n  id: id",
+			want: VariablesToSet{
+				Sources: map[int]map[string]string{200: {"token": "header:Authorization", "id": "id"}},
+				Secrets: []string{"token"},
+			},
+		},
+		{
+			name:    "invalid secret source",
+			data:    "200:\n  token:\n    path: access_token",
+			wantErr: "variable token for status 200: unknown key path, source and secret are allowed",
+		},
+		{
+			name:    "invalid source",
+			data:    "200:\n  id: [1]",
+			wantErr: "source of variable id for status 200 must be a string",
+		},
+		{
+			name:    "invalid code",
+			data:    "20: id",
+			wantErr: "invalid status code 20 in response key 20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res VariablesToSet
+			err := yaml.Unmarshal([]byte(tt.data), &res)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, res)
+		})
+	}
+}
@@ -3,23 +3,27 @@ package yaml_file
 import (
 	"reflect"
 	"testing"
+
+	"github.com/lamoda/gonkey/models"
 )
 
 func TestNewTestWithCases(t *testing.T) {
 	data := TestDefinition{
 		RequestTmpl: `{"foo": "bar", "hello": {{ .hello }} }`,
-		ResponseTmpls: map[int]string{
-			200: `{"foo": "bar", "hello": {{ .hello }} }`,
-			400: `{"foo": "bar", "hello": {{ .hello }} }`,
+		ResponseTmpls: ResponseTemplates{
+			Codes: map[int]string{
+				200: `{"foo": "bar", "hello": {{ .hello }} }`,
+				400: `{"foo": "bar", "hello": {{ .hello }} }`,
+			},
 		},
-		ResponseHeaders: map[int]map[string]string{
+		ResponseHeaders: map[int]map[string]models.HeaderValue{
 			200: {
-				"hello": "world",
-				"say":   "hello",
+				"hello": models.NewHeaderValue("world"),
+				"say":   models.NewHeaderValue("hello"),
 			},
 			400: {
-				"hello": "world",
-				"foo":   "bar",
+				"hello": models.NewHeaderValue("world"),
+				"foo":   models.NewHeaderValues("bar", "{{ .hello }}"),
 			},
 		},
 		Cases: []CaseData{
@@ -80,4 +84,9 @@ func TestNewTestWithCases(t *testing.T) {
 	if !reflect.DeepEqual(filename, "cases/example.yaml") {
 		t.Errorf("want filename %s, got %s", "cases/example.yaml", filename)
 	}
+
+	headers, _ := tests[1].GetResponseHeaders(400)
+	if want := models.NewHeaderValues("bar", "world2"); !reflect.DeepEqual(headers["foo"], want) {
+		t.Errorf("want response header %v, got %v", want, headers["foo"])
+	}
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 
 	"github.com/lamoda/gonkey/models"
 	"github.com/lamoda/gonkey/variables"
@@ -142,3 +143,41 @@ func checkApplied(t *testing.T, test models.TestInterface, combined bool) {
 		assert.Equal(t, "some_value - redefined_value", resp)
 	}
 }
+
+func TestApplyVariablesToAllFields(t *testing.T) {
+	tests, err := parseTestDefinitionFile("testdata/variables-all-fields.yaml")
+	require.NoError(t, err)
+	require.Len(t, tests, 1)
+
+	vars := variables.New()
+	vars.Load(map[string]string{"session": "abc", "tenant": "7", "db": "main", "timeout": "3", "pause": "2"})
+	test := vars.Apply(&tests[0])
+
+	assert.Equal(t, map[string]string{"session": "abc"}, test.Cookies())
+	assert.Equal(t, []string{"users_7"}, test.Fixtures())
+	assert.Equal(t, models.FixturesMultiDb{{DbName: "main", Files: []string{"orders_7"}}}, test.FixturesMultiDb())
+	assert.Equal(t, "scripts/7.sh", test.BeforeScriptPath())
+	assert.Equal(t, 3, test.BeforeScriptTimeout())
+	assert.Equal(t, "scripts/after_7.sh", test.AfterRequestScriptPath())
+	assert.Equal(t, 2, test.Pause())
+
+	headers, ok := test.GetResponseHeaders(200)
+	require.True(t, ok)
+	assert.Equal(t, []string{"7"}, headers["X-Tenant"].Values)
+
+	require.Len(t, test.GetDatabaseChecks(), 1)
+	assert.Equal(t, "main", test.GetDatabaseChecks()[0].DbNameString())
+	assert.Equal(t, "SELECT * FROM users WHERE tenant = 7", test.GetDatabaseChecks()[0].DbQueryString())
+	assert.Equal(t, []string{`{"tenant": 7}`}, test.GetDatabaseChecks()[0].DbResponseJson())
+
+	// original test is not changed
+	assert.Equal(t, map[string]string{"session": "{{ $session }}"}, tests[0].Cookies())
+	assert.Equal(t, "{{ $db }}", tests[0].GetDatabaseChecks()[0].DbNameString())
+	assert.Equal(t, 0, tests[0].Pause())
+}
+
+func TestParseInvalidPause(t *testing.T) {
+	var definition TestDefinition
+	err := yaml.Unmarshal([]byte("pause: soon"), &definition)
+	assert.EqualError(t, err, `invalid integer "soon"`)
+}
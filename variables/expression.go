@@ -0,0 +1,60 @@
+package variables
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// filtersPattern matches filters of variable expression: | json, | default "value"
+const filtersPattern = `(?:\|\s*(?:json|default\s*"(?:[^"\\]|\\.)*")\s*)*`
+
+// expressionRx matches variables with optional filters: {{ $name }}, {{ $name | json }}, {{ $name | default "x" }}
+var expressionRx = regexp.MustCompile(`{{\s*\$(\w+)\s*(` + filtersPattern + `)}}`)
+
+// jsonValueRx matches strings consisting only of variable expression, in values of any type (mocks, claims of jwt, etc.)
+// such strings with json filter are replaced with the value keeping its type
+var jsonValueRx = regexp.MustCompile(`^\s*` + expressionRx.String() + `\s*$`)
+
+var filterRx = regexp.MustCompile(`\|\s*(?:(json)|default\s*("(?:[^"\\]|\\.)*"))`)
+
+// expression is a variable with filters: json gives the value as JSON,
+// default gives the value used when the variable is not defined
+type expression struct {
+	name         string
+	json         bool
+	defaultValue string
+	hasDefault   bool
+}
+
+// parseExpression parses submatches of expressionRx
+func parseExpression(submatches []string) expression {
+	expr := expression{name: submatches[1]}
+	for _, filter := range filterRx.FindAllStringSubmatch(submatches[2], -1) {
+		if filter[1] != "" {
+			expr.json = true
+
+			continue
+		}
+		expr.hasDefault = true
+		expr.defaultValue, _ = strconv.Unquote(filter[2])
+	}
+
+	return expr
+}
+
+// evaluate returns value of the expression, false is returned when the variable is not defined
+// and the expression has no default value
+func (e expression) evaluate(v *Variable) (string, bool) {
+	switch {
+	case v != nil && e.json:
+		return v.json, true
+	case v != nil:
+		return v.value, true
+	case e.hasDefault && e.json:
+		return jsonString(e.defaultValue), true
+	case e.hasDefault:
+		return e.defaultValue, true
+	default:
+		return "", false
+	}
+}
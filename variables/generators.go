@@ -0,0 +1,261 @@
+package variables
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// generatorRx matches calls of generator functions: {{ $uuid() }}, {{ $randInt(1, 100) }}, {{ $fake.email }}
+var generatorRx = regexp.MustCompile(`{{\s*\$(\w+(?:\.\w+)?)\s*(\(([^(){}]*)\))?\s*}}`)
+
+// generatorEnv is passed to generator functions
+type generatorEnv struct {
+	rnd *rand.Rand
+	now time.Time
+}
+
+type generatorFunc func(env *generatorEnv, args []string) (string, error)
+
+var generatorFuncs = map[string]generatorFunc{
+	"uuid":           generateUUID,
+	"now":            generateNow,
+	"randInt":        generateRandInt,
+	"randString":     generateRandString,
+	"fake.firstName": fakeValue(func(rnd *rand.Rand) string { return pick(rnd, firstNames) }),
+	"fake.lastName":  fakeValue(func(rnd *rand.Rand) string { return pick(rnd, lastNames) }),
+	"fake.name": fakeValue(func(rnd *rand.Rand) string {
+		return pick(rnd, firstNames) + " " + pick(rnd, lastNames)
+	}),
+	"fake.email": fakeValue(func(rnd *rand.Rand) string {
+		return fmt.Sprintf(
+			"%s.%s%04d@%s",
+			strings.ToLower(pick(rnd, firstNames)),
+			strings.ToLower(pick(rnd, lastNames)),
+			rnd.Intn(10000),
+			pick(rnd, emailDomains),
+		)
+	}),
+	"fake.username": fakeValue(func(rnd *rand.Rand) string {
+		return fmt.Sprintf("%s%04d", strings.ToLower(pick(rnd, firstNames)), rnd.Intn(10000))
+	}),
+	"fake.phone": fakeValue(func(rnd *rand.Rand) string {
+		return fmt.Sprintf("+1555%07d", rnd.Intn(10000000))
+	}),
+}
+
+var (
+	firstNames = []string{
+		"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda",
+		"William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+	}
+	lastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+		"Rodriguez", "Martinez", "Hernandez", "Lopez", "Wilson", "Anderson", "Thomas", "Taylor",
+	}
+	// reserved domains, so generated emails never reach real mailboxes
+	emailDomains = []string{"example.com", "example.org", "example.net"}
+)
+
+const randStringAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// generator produces values of generator functions reproducible with the seed.
+// Every templated string gets its own random source derived from the seed, the string
+// and the number of times it was generated, so values do not depend on the order
+// fields of the test are processed in.
+type generator struct {
+	seed   int64
+	counts map[string]int
+	now    func() time.Time
+}
+
+func newGenerator(seed int64) *generator {
+	return &generator{seed: seed, counts: map[string]int{}, now: time.Now}
+}
+
+// generate replaces calls of generator functions in str with generated values,
+// unknown functions and calls with invalid arguments are left as is
+func (g *generator) generate(str string) string {
+	if g == nil || !strings.Contains(str, "{{") {
+		return str
+	}
+
+	var env *generatorEnv
+
+	return generatorRx.ReplaceAllStringFunc(str, func(match string) string {
+		submatches := generatorRx.FindStringSubmatch(match)
+		name, isCall := submatches[1], submatches[2] != ""
+		// {{ $name }} without arguments is a variable
+		if !isCall && !strings.Contains(name, ".") {
+			return match
+		}
+
+		fn, ok := generatorFuncs[name]
+		if !ok {
+			return match
+		}
+
+		if env == nil {
+			env = &generatorEnv{rnd: g.source(str), now: g.now()}
+		}
+
+		value, err := fn(env, parseArgs(submatches[3]))
+		if err != nil {
+			return match
+		}
+
+		return value
+	})
+}
+
+func (g *generator) source(str string) *rand.Rand {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d\x00%s\x00%d", g.seed, str, g.counts[str])
+	g.counts[str]++
+
+	return rand.New(rand.NewSource(int64(h.Sum64()))) //nolint:gosec // values are not used for security
+}
+
+// parseArgs splits comma separated arguments, double quotes around arguments are removed
+func parseArgs(str string) []string {
+	if strings.TrimSpace(str) == "" {
+		return nil
+	}
+
+	var args []string
+	var current strings.Builder
+	quoted := false
+	for _, c := range str {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			args = append(args, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(c)
+		}
+	}
+
+	return append(args, strings.TrimSpace(current.String()))
+}
+
+func generateUUID(env *generatorEnv, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", fmt.Errorf("uuid has no arguments")
+	}
+
+	id, err := uuid.NewRandomFromReader(env.rnd)
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+// generateNow formats current time shifted by optional offset (+1h, -30m, +2d) with layout of time package,
+// unix and unixMilli layouts give timestamps. Current time does not depend on the seed.
+func generateNow(env *generatorEnv, args []string) (string, error) {
+	now := env.now
+	layout := time.RFC3339
+	if len(args) > 0 && args[0] != "" {
+		layout = args[0]
+	}
+	if len(args) > 1 {
+		offset, err := parseOffset(args[1])
+		if err != nil {
+			return "", err
+		}
+		now = now.Add(offset)
+	}
+	if len(args) > 2 {
+		return "", fmt.Errorf("now has at most two arguments")
+	}
+
+	switch layout {
+	case "unix":
+		return strconv.FormatInt(now.Unix(), 10), nil
+	case "unixMilli":
+		return strconv.FormatInt(now.UnixMilli(), 10), nil
+	default:
+		return now.Format(layout), nil
+	}
+}
+
+func parseOffset(str string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(str, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(str)
+}
+
+// generateRandInt returns random integer between min and max inclusive
+func generateRandInt(env *generatorEnv, args []string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf("randInt requires min and max")
+	}
+	lo, err := strconv.Atoi(args[0])
+	if err != nil {
+		return "", err
+	}
+	hi, err := strconv.Atoi(args[1])
+	if err != nil {
+		return "", err
+	}
+	if hi < lo {
+		return "", fmt.Errorf("max %d is less than min %d", hi, lo)
+	}
+
+	return strconv.Itoa(lo + env.rnd.Intn(hi-lo+1)), nil
+}
+
+// generateRandString returns random alphanumeric string of given length, 16 by default
+func generateRandString(env *generatorEnv, args []string) (string, error) {
+	length := 16
+	if len(args) > 1 {
+		return "", fmt.Errorf("randString has at most one argument")
+	}
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "", err
+		}
+		if n < 0 {
+			return "", fmt.Errorf("length %d is negative", n)
+		}
+		length = n
+	}
+
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = randStringAlphabet[env.rnd.Intn(len(randStringAlphabet))]
+	}
+
+	return string(b), nil
+}
+
+func fakeValue(fn func(rnd *rand.Rand) string) generatorFunc {
+	return func(env *generatorEnv, args []string) (string, error) {
+		if len(args) != 0 {
+			return "", fmt.Errorf("fake values have no arguments")
+		}
+
+		return fn(env.rnd), nil
+	}
+}
+
+func pick(rnd *rand.Rand, values []string) string {
+	return values[rnd.Intn(len(values))]
+}
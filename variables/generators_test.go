@@ -0,0 +1,98 @@
+package variables
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	g := newGenerator(42)
+	g.now = func() time.Time { return time.Date(2024, 1, 31, 22, 30, 0, 0, time.UTC) }
+
+	tests := []struct {
+		template string
+		pattern  string
+	}{
+		{`{{ $uuid() }}`, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{`{{ $now() }}`, `^2024-01-31T22:30:00Z$`},
+		{`{{ $now("2006-01-02", "+2h") }}`, `^2024-02-01$`},
+		{`{{ $now("unix", "-1d") }}`, `^1706653800$`},
+		{`{{ $now(unixMilli) }}`, `^1706740200000$`},
+		{`{{ $randInt(1, 3) }}`, `^[1-3]$`},
+		{`{{ $randString(8) }}`, `^[a-zA-Z0-9]{8}$`},
+		{`{{ $fake.email }}`, `^[a-z]+\.[a-z]+\d{4}@example\.(com|org|net)$`},
+		{`{{ $fake.name }}`, `^[A-Z][a-z]+ [A-Z][a-z]+$`},
+		{`{{ $fake.phone }}`, `^\+1555\d{7}$`},
+		{`id-{{$uuid()}}-{{ $randInt(5,5) }}`, `^id-[0-9a-f-]{36}-5$`},
+	}
+
+	for _, tt := range tests {
+		assert.Regexp(t, regexp.MustCompile(tt.pattern), g.generate(tt.template), tt.template)
+	}
+}
+
+func TestGenerateKeepsUnknownExpressions(t *testing.T) {
+	g := newGenerator(42)
+
+	for _, template := range []string{
+		`{{ $name }}`,
+		`{{ $unknown() }}`,
+		`{{ $fake.unknown }}`,
+		`{{ $randInt(1) }}`,
+		`{{ $randInt(5, 1) }}`,
+		`{{ $now("2006", "soon") }}`,
+		`{{ $uuid(1) }}`,
+	} {
+		assert.Equal(t, template, g.generate(template))
+	}
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	templates := []string{`{{ $uuid() }}`, `{{ $fake.email }}`, `{{ $uuid() }}`, `{{ $randInt(1, 1000000) }}`}
+
+	generateAll := func(seed int64) []string {
+		g := newGenerator(seed)
+		res := make([]string, len(templates))
+		for i, template := range templates {
+			res[i] = g.generate(template)
+		}
+
+		return res
+	}
+
+	first := generateAll(7)
+	assert.Equal(t, first, generateAll(7))
+	assert.NotEqual(t, first, generateAll(8))
+	// the same template gives new value every time
+	assert.NotEqual(t, first[0], first[2])
+}
+
+func TestVariablesGenerate(t *testing.T) {
+	vs := New()
+	vs.SetSeed(1)
+	assert.Equal(t, int64(1), vs.Seed())
+
+	values := vs.Generate(map[string]string{
+		"first":  "{{ $fake.email }}",
+		"second": "{{ $fake.email }}",
+		"plain":  "{{ $name }}",
+	})
+	assert.NotEqual(t, values["first"], values["second"])
+	assert.Equal(t, "{{ $name }}", values["plain"])
+
+	replayed := New()
+	replayed.SetSeed(1)
+	assert.Equal(t, values, replayed.Generate(map[string]string{
+		"first":  "{{ $fake.email }}",
+		"second": "{{ $fake.email }}",
+		"plain":  "{{ $name }}",
+	}))
+
+	vs.Set("name", "gonkey")
+	assert.Regexp(t, `^gonkey-[1-9]$`, vs.perform("{{ $name }}-{{ $randInt(1, 9) }}"))
+	require.Nil(t, vs.Generate(nil))
+}
@@ -0,0 +1,74 @@
+package variables
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Export returns values of suite scope and file scope of the last test file as JSON by names,
+// file variables hide suite variables with the same names. Values of secret variables are exported as is.
+func (vs *Variables) Export() map[string]json.RawMessage {
+	res := make(map[string]json.RawMessage, len(vs.variables)+len(vs.fileVars))
+	for _, scope := range []variables{vs.variables, vs.fileVars} {
+		for n, v := range scope {
+			res[n] = json.RawMessage(v.json)
+		}
+	}
+
+	return res
+}
+
+// SaveFile writes exported variables to JSON file, it can be loaded with LoadFile in the following runs
+func (vs *Variables) SaveFile(path string) error {
+	data, err := json.MarshalIndent(vs.Export(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("can't encode variables: %w", err)
+	}
+
+	// the file may contain secrets
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("can't write variables to %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// LoadFile adds variables from JSON file with object of values (as written by SaveFile) or from env-file
+// to suite scope. Values of JSON file keep their types, other files are read as env-files.
+func (vs *Variables) LoadFile(path string) error {
+	source := "file " + path
+
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		values, err := godotenv.Read(path)
+		if err != nil {
+			return fmt.Errorf("can't read variables from %s: %w", path, err)
+		}
+		vs.loadScoped(vs.variables, ScopeSuite, values, source)
+
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't read variables from %s: %w", path, err)
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("variables file %s must contain JSON object: %w", path, err)
+	}
+
+	for n, raw := range values {
+		v, err := NewJSONVariable(n, string(raw))
+		if err != nil {
+			return err
+		}
+		vs.setScoped(vs.variables, ScopeSuite, n, v, source)
+	}
+
+	return nil
+}
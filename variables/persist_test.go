@@ -0,0 +1,59 @@
+package variables
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoadFile(t *testing.T) {
+	vars, err := FromResponse(map[string]string{"user": "user", "id": "user.id"}, `{"user": {"id": 7}}`, true)
+	require.NoError(t, err)
+
+	vs := New()
+	vs.Set("token", "tok-1")
+	vs.Set("id", "suite")
+	vs.EnterFile("a.yaml", nil)
+	vs.SetFromTest(vars, "")
+	vs.EnterTest("test", map[string]string{"local": "value"}, nil)
+
+	path := filepath.Join(t.TempDir(), "vars.json")
+	require.NoError(t, vs.SaveFile(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"token": "tok-1", "id": 7, "user": {"id": 7}}`, string(data))
+
+	loaded := New()
+	require.NoError(t, loaded.LoadFile(path))
+	assert.Equal(t, `{"user": {"id":7}, "id": 7, "token": tok-1}`,
+		loaded.Perform(`{"user": {{ $user | json }}, "id": {{ $id | json }}, "token": {{ $token }}}`))
+	assert.Contains(t, loaded.Dump(), `token = "tok-1" (suite, file `+path+`)`)
+}
+
+func TestLoadFilePrecedence(t *testing.T) {
+	t.Setenv("host", "from-environment")
+
+	path := filepath.Join(t.TempDir(), "vars.env")
+	require.NoError(t, os.WriteFile(path, []byte("host=from-file\nport=8080\n"), 0o600))
+
+	vs := New()
+	require.NoError(t, vs.LoadFile(path))
+	assert.Equal(t, "from-file:8080", vs.Perform("{{ $host }}:{{ $port }}"))
+
+	vs.EnterTest("test", map[string]string{"host": "from-test"}, nil)
+	assert.Equal(t, "from-test", vs.Perform("{{ $host }}"))
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vars.json")
+	require.NoError(t, os.WriteFile(path, []byte(`["id"]`), 0o600))
+
+	vs := New()
+	assert.Error(t, vs.LoadFile(path))
+	assert.Error(t, vs.LoadFile(filepath.Join(dir, "missing.env")))
+}
@@ -1,11 +1,122 @@
 package variables
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/tidwall/gjson"
+
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/xmlparsing"
+)
+
+// prefixes of sources of variables_to_set, sources without prefix are paths in the body,
+// so status without colon remains the path of JSON body
+const (
+	sourceHeader = "header:"
+	sourceCookie = "cookie:"
+	sourceStatus = "status:"
+	sourceXPath  = "xpath:"
+	sourceRegex  = "regex:"
 )
 
+// FromResult makes variables from the response of the test. Sources are header:<name>, cookie:<name>, status:,
+// xpath:<expression> and regex:<pattern> over the body (the first group or the whole match), other sources
+// are paths in JSON body, the body of other content types is stored as a whole.
+func FromResult(varsToSet map[string]string, result *models.Result, namespaces map[string]string) (*Variables, error) {
+	vars := New()
+	bodyVars := make(map[string]string)
+	xpathVars := make(map[string]string)
+
+	for name, source := range varsToSet {
+		var value string
+		var err error
+		switch {
+		case strings.HasPrefix(source, sourceHeader):
+			value, err = fromHeader(strings.TrimPrefix(source, sourceHeader), result.ResponseHeaders)
+		case strings.HasPrefix(source, sourceCookie):
+			value, err = fromCookie(strings.TrimPrefix(source, sourceCookie), result.ResponseHeaders)
+		case source == sourceStatus:
+			value = strconv.Itoa(result.ResponseStatusCode)
+		case strings.HasPrefix(source, sourceRegex):
+			value, err = fromRegex(strings.TrimPrefix(source, sourceRegex), result.ResponseBody)
+		case strings.HasPrefix(source, sourceXPath):
+			xpathVars[name] = strings.TrimPrefix(source, sourceXPath)
+
+			continue
+		default:
+			bodyVars[name] = source
+
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		vars.Add(NewVariable(name, value))
+	}
+
+	if len(xpathVars) > 0 {
+		xmlVars, err := FromXMLResponse(xpathVars, result.ResponseBody, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		vars.Merge(xmlVars)
+	}
+
+	if len(bodyVars) > 0 {
+		isJSON := strings.Contains(result.ResponseContentType, "json") && result.ResponseBody != ""
+		fromBody, err := FromResponse(bodyVars, result.ResponseBody, isJSON)
+		if err != nil {
+			return nil, err
+		}
+		vars.Merge(fromBody)
+	}
+
+	return vars, nil
+}
+
+func fromHeader(name string, headers map[string][]string) (string, error) {
+	values, ok := http.Header(headers)[http.CanonicalHeaderKey(name)]
+	if !ok || len(values) == 0 {
+		return "", fmt.Errorf("header '%s' doesn't exist in response", name)
+	}
+
+	return values[0], nil
+}
+
+func fromCookie(name string, headers map[string][]string) (string, error) {
+	resp := http.Response{Header: headers}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == name {
+			return cookie.Value, nil
+		}
+	}
+
+	return "", fmt.Errorf("cookie '%s' isn't set by response", name)
+}
+
+func fromRegex(pattern, body string) (string, error) {
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex '%s': %w", pattern, err)
+	}
+
+	match := rx.FindStringSubmatch(body)
+	switch {
+	case match == nil:
+		return "", fmt.Errorf("regex '%s' doesn't match response body", pattern)
+	case len(match) > 1:
+		return match[1], nil
+	default:
+		return match[0], nil
+	}
+}
+
 func FromResponse(varsToSet map[string]string, body string, isJSON bool) (vars *Variables, err error) {
 	names, paths := split(varsToSet)
 
@@ -36,7 +147,68 @@ func fromJSON(names, paths []string, body string) (*Variables, error) {
 				fmt.Errorf("path '%s' doesn't exist in given json", paths[n])
 		}
 
-		vars.Add(NewVariable(names[n], res.String()))
+		// values keep their JSON type, so they can be substituted as JSON with {{ $name | json }}
+		vars.Add(newVariable(names[n], res.String(), res.Raw))
+	}
+
+	return vars, nil
+}
+
+// FromXMLResponse makes variables from XML response, paths are XPath expressions with prefixes
+// resolved by namespaces. Variables with empty path get the whole body.
+func FromXMLResponse(varsToSet map[string]string, body string, namespaces map[string]string) (*Variables, error) {
+	var doc *xmlparsing.Document
+
+	vars := New()
+	for name, path := range varsToSet {
+		if path == "" {
+			vars.Add(NewVariable(name, body))
+
+			continue
+		}
+
+		if doc == nil {
+			var err error
+			if doc, err = xmlparsing.ParseDocument(body); err != nil {
+				return nil, fmt.Errorf("can't parse xml response: %w", err)
+			}
+		}
+
+		res, err := doc.Query(path, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		if !res.Exists() {
+			return nil, fmt.Errorf("xpath '%s' doesn't select any node in given xml", path)
+		}
+
+		vars.Add(NewVariable(name, res.String()))
+	}
+
+	return vars, nil
+}
+
+// FromCaptures makes variables from values captured by $capture in expected response body.
+// Strings are stored as is, other values are stored as JSON (the same way as values from JSON response).
+func FromCaptures(captures map[string]interface{}) (*Variables, error) {
+	vars := New()
+
+	for name, value := range captures {
+		if str, ok := value.(string); ok {
+			vars.Add(NewVariable(name, str))
+
+			continue
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert captured value of variable '%s': %w", name, err)
+		}
+		variable, err := NewJSONVariable(name, string(data))
+		if err != nil {
+			return nil, err
+		}
+		vars.Add(variable)
 	}
 
 	return vars, nil
@@ -0,0 +1,73 @@
+package variables
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+func TestFromResult(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode:  201,
+		ResponseContentType: "application/json",
+		ResponseBody:        `{"id": 42, "status": "created", "message": "order 42 is created"}`,
+		ResponseHeaders: map[string][]string{
+			"Location":   {"/orders/42"},
+			"Set-Cookie": {"lang=en; Path=/", "sid=abc123; HttpOnly"},
+		},
+	}
+
+	vars, err := FromResult(map[string]string{
+		"id":       "id",
+		"status":   "status",
+		"code":     "status:",
+		"location": "header:location",
+		"sid":      "cookie:sid",
+		"orderId":  `regex:order (\d+)`,
+		"order":    `regex:order \d+`,
+	}, result, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "42 created 201 /orders/42 abc123 42 order 42", vars.perform(
+		"{{ $id }} {{ $status }} {{ $code }} {{ $location }} {{ $sid }} {{ $orderId }} {{ $order }}",
+	))
+}
+
+func TestFromResultXPath(t *testing.T) {
+	result := &models.Result{
+		ResponseStatusCode:  500,
+		ResponseContentType: "text/xml",
+		ResponseBody: `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+			<soap:Body><soap:Fault><faultcode>soap:Server</faultcode></soap:Fault></soap:Body>
+		</soap:Envelope>`,
+	}
+
+	vars, err := FromResult(map[string]string{
+		"fault": "xpath://s:Fault/faultcode",
+		"code":  "xpath://faultcode",
+	}, result, map[string]string{"s": "http://schemas.xmlsoap.org/soap/envelope/"})
+	require.NoError(t, err)
+	assert.Equal(t, "soap:Server soap:Server", vars.perform("{{ $fault }} {{ $code }}"))
+
+	// sources without prefix store the whole body of XML response, as for other bodies which are not JSON
+	vars, err = FromResult(map[string]string{"body": "//faultcode"}, result, nil)
+	require.NoError(t, err)
+	assert.Equal(t, result.ResponseBody, vars.perform("{{ $body }}"))
+}
+
+func TestFromResultErrors(t *testing.T) {
+	result := &models.Result{ResponseBody: "plain text", ResponseHeaders: map[string][]string{}}
+
+	for source, want := range map[string]string{
+		"header:Location": "header 'Location' doesn't exist in response",
+		"cookie:sid":      "cookie 'sid' isn't set by response",
+		"regex:[0-9]+":    "regex '[0-9]+' doesn't match response body",
+		"regex:(":         "invalid regex '(': error parsing regexp: missing closing ): `(`",
+	} {
+		_, err := FromResult(map[string]string{"name": source}, result, nil)
+		assert.EqualError(t, err, want, source)
+	}
+}
@@ -0,0 +1,157 @@
+package variables
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// SecretEnvPrefix marks secret variables by name: values of environment variables (and variables of env-file)
+// named GONKEY_SECRET_TOKEN, GONKEY_SECRET_PASSWORD, etc. are masked in outputs
+const SecretEnvPrefix = "GONKEY_SECRET_"
+
+// Redacted replaces values of secret variables in outputs
+const Redacted = "***"
+
+// MarkSecret makes values of variables with given names masked in outputs till the end of the run
+func (vs *Variables) MarkSecret(names ...string) {
+	for _, n := range names {
+		vs.secrets[strings.TrimPrefix(n, GlobalPrefix)] = true
+	}
+}
+
+func (vs *Variables) isSecret(name string) bool {
+	return vs.secrets[name] || strings.HasPrefix(name, SecretEnvPrefix)
+}
+
+// Mask replaces values of secret variables in str with Redacted
+func (vs *Variables) Mask(str string) string {
+	if mask := vs.Masker(); mask != nil {
+		return mask(str)
+	}
+
+	return str
+}
+
+// Masker returns function replacing values of secret variables with Redacted, nil is returned when there are
+// no secret values. Values are remembered, so they are masked after the variables go out of scope.
+func (vs *Variables) Masker() func(string) string {
+	return vs.secretReplacer(func(string) string {
+		return Redacted
+	})
+}
+
+// Placeholders returns function replacing values of secret variables with the variables ({{ $name }}),
+// nil is returned when there are no secret values
+func (vs *Variables) Placeholders() func(string) string {
+	return vs.secretReplacer(func(name string) string {
+		return "{{ $" + name + " }}"
+	})
+}
+
+func (vs *Variables) secretReplacer(replacement func(name string) string) func(string) string {
+	if vs == nil {
+		return nil
+	}
+
+	for _, scope := range []variables{vs.variables, vs.fileVars, vs.testVars, vs.caseVars} {
+		for n, v := range scope {
+			if vs.isSecret(n) {
+				vs.addSecretValue(n, v.value)
+			}
+		}
+	}
+	for n := range vs.secrets {
+		vs.addSecretValue(n, os.Getenv(n))
+	}
+	for _, env := range os.Environ() {
+		if name, value, _ := strings.Cut(env, "="); strings.HasPrefix(name, SecretEnvPrefix) {
+			vs.addSecretValue(name, value)
+		}
+	}
+
+	if len(vs.secretValues) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(vs.secretValues))
+	for value := range vs.secretValues {
+		values = append(values, value)
+	}
+	// longer values go first, so a secret containing another one is masked as a whole
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+
+		return values[i] < values[j]
+	})
+
+	pairs := make([]string, 0, 2*len(values))
+	for _, value := range values {
+		pairs = append(pairs, value, replacement(vs.secretValues[value]))
+	}
+
+	return strings.NewReplacer(pairs...).Replace
+}
+
+// addSecretValue remembers value of secret variable, the value shared by several variables keeps the least name
+func (vs *Variables) addSecretValue(name, value string) {
+	if known, ok := vs.secretValues[value]; value != "" && (!ok || name < known) {
+		vs.secretValues[value] = name
+	}
+}
+
+// MaskResult returns copies of the test and its result with values of secret variables replaced with Redacted,
+// so they can be passed to outputs. Errors whose messages contain secrets are wrapped into errors
+// with masked messages, errors.As still finds the original types of them.
+func (vs *Variables) MaskResult(t models.TestInterface, result *models.Result) (models.TestInterface, *models.Result) {
+	return replaceInResult(t, result, vs.Masker())
+}
+
+// PlaceholderResult returns copies of the test and its result with values of secret variables replaced
+// with the variables ({{ $name }}), so they can be written to test files (e.g. by the snapshot updater)
+func (vs *Variables) PlaceholderResult(t models.TestInterface, result *models.Result) (models.TestInterface, *models.Result) {
+	return replaceInResult(t, result, vs.Placeholders())
+}
+
+func replaceInResult(t models.TestInterface, result *models.Result, mask func(string) string) (models.TestInterface, *models.Result) {
+	if mask == nil {
+		return t, result
+	}
+
+	maskedTest := replaceValue(reflect.ValueOf(t), mask, nil).Interface().(models.TestInterface)
+	maskedResult := replaceValue(reflect.ValueOf(result), mask, nil).Interface().(*models.Result)
+	for i, err := range maskedResult.Errors {
+		maskedResult.Errors[i] = MaskError(err, mask)
+	}
+
+	return maskedTest, maskedResult
+}
+
+// MaskError returns err with values of secret variables masked in its message by mask,
+// errors.As still finds the original type of it. err is returned as is when its message has no secrets.
+func MaskError(err error, mask func(string) string) error {
+	if message := mask(err.Error()); message != err.Error() {
+		return &maskedError{error: err, message: message}
+	}
+
+	return err
+}
+
+// maskedError is the error with values of secret variables masked in its message
+type maskedError struct {
+	error
+	message string
+}
+
+func (e *maskedError) Error() string {
+	return e.message
+}
+
+func (e *maskedError) Unwrap() error {
+	return e.error
+}
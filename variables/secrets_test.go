@@ -0,0 +1,92 @@
+package variables
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lamoda/gonkey/compare"
+	"github.com/lamoda/gonkey/models"
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func TestMask(t *testing.T) {
+	t.Setenv("GONKEY_SECRET_PASSWORD", "env-secret")
+
+	vs := New()
+	assert.Equal(t, "token tok-1", vs.Mask("token tok-1"), "there are no secret variables")
+
+	vs.Set("token", "tok-1")
+	vs.Set("long", "tok-1-long")
+	vs.MarkSecret("token", "global:long")
+
+	assert.Equal(t, "token ***, *** and ***", vs.Mask("token tok-1, tok-1-long and env-secret"))
+	assert.Equal(t, "long = \"***\" (suite)\ntoken = \"***\" (suite)\n", vs.Dump())
+
+	// values stay masked when variables go out of scope
+	vs.EnterTest("test", map[string]string{"token": "tok-2"}, nil)
+	assert.Equal(t, "***", vs.Mask("tok-2"))
+	vs.EnterTest("next", nil, nil)
+	assert.Equal(t, "*** ***", vs.Mask("tok-1 tok-2"))
+}
+
+func TestPlaceholders(t *testing.T) {
+	t.Setenv("GONKEY_SECRET_PASSWORD", "env-secret")
+
+	vs := New()
+	vs.Set("token", "tok-1")
+	vs.Set("long", "tok-1-long")
+	vs.MarkSecret("token", "long")
+
+	assert.Equal(t, "token {{ $token }}, {{ $long }} and {{ $GONKEY_SECRET_PASSWORD }}",
+		vs.Placeholders()("token tok-1, tok-1-long and env-secret"))
+
+	test := &yaml_file.Test{}
+	_, result := vs.PlaceholderResult(test, &models.Result{ResponseBody: `{"token": "tok-1"}`})
+	assert.Equal(t, `{"token": "{{ $token }}"}`, result.ResponseBody)
+}
+
+func TestMaskResult(t *testing.T) {
+	vs := New()
+	vs.Set("token", "tok-1")
+	vs.MarkSecret("token")
+
+	test := &yaml_file.Test{TestDefinition: yaml_file.TestDefinition{
+		HeadersVal: map[string]string{"Authorization": "Bearer tok-1"},
+		Variables:  map[string]string{"token": "tok-1"},
+	}}
+	mismatch := &compare.MismatchError{Path: "$.token", Kind: compare.MismatchValues, Expected: "tok-1", Actual: "tok-2"}
+	result := &models.Result{
+		RequestBody:     `{"token": "tok-1"}`,
+		ResponseHeaders: map[string][]string{"X-Token": {"tok-1"}},
+		Errors: []error{
+			&models.CheckError{Category: models.ErrorCategoryResponseBody, Err: mismatch},
+			fmt.Errorf("unexpected token tok-1"),
+		},
+		Test: test,
+	}
+
+	maskedTest, maskedResult := vs.MaskResult(test, result)
+
+	assert.Equal(t, map[string]string{"Authorization": "Bearer ***"}, maskedTest.Headers())
+	assert.Equal(t, map[string]string{"token": "***"}, maskedTest.GetVariables())
+	assert.Equal(t, `{"token": "***"}`, maskedResult.RequestBody)
+	assert.Equal(t, []string{"***"}, maskedResult.ResponseHeaders["X-Token"])
+	assert.Equal(t, map[string]string{"Authorization": "Bearer ***"}, maskedResult.Test.Headers())
+	assert.Equal(t, "unexpected token ***", maskedResult.Errors[1].Error())
+
+	var maskedMismatch *compare.MismatchError
+	require.True(t, errors.As(maskedResult.Errors[0], &maskedMismatch))
+	assert.Equal(t, "***", maskedMismatch.Expected)
+	var checkErr *models.CheckError
+	require.True(t, errors.As(maskedResult.Errors[0], &checkErr))
+	assert.Equal(t, models.ErrorCategoryResponseBody, checkErr.GetCategory())
+
+	// originals are not changed
+	assert.Equal(t, map[string]string{"Authorization": "Bearer tok-1"}, test.Headers())
+	assert.Equal(t, `{"token": "tok-1"}`, result.RequestBody)
+	assert.Equal(t, "tok-1", mismatch.Expected)
+}
@@ -0,0 +1,142 @@
+package variables
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lamoda/gonkey/models"
+)
+
+// UnresolvedVariable is a variable left in the field of the test after substitution,
+// because it is not defined and has no default value
+type UnresolvedVariable struct {
+	Name string
+	// Field is the path of the field as it is written in test file: headers.Authorization, mocks.auth.body
+	Field string
+}
+
+func (u UnresolvedVariable) String() string {
+	return fmt.Sprintf("$%s in %s", u.Name, u.Field)
+}
+
+// Unresolved returns variables left in fields of the test after Apply. Fields are named by their YAML keys.
+// Fields filled by the loader from the ones of the test file (request with arguments of the case, etc.)
+// are named after Go fields and reported only for variables not found in the fields of the test file.
+func Unresolved(t models.TestInterface) []UnresolvedVariable {
+	found := make(map[UnresolvedVariable]bool)
+	written := make(map[string]bool)
+	var derived []UnresolvedVariable
+	visitStrings(reflect.ValueOf(t), "", false, func(field, str string, isDerived bool) {
+		for _, match := range expressionRx.FindAllStringSubmatch(str, -1) {
+			u := UnresolvedVariable{Name: match[1], Field: field}
+			if isDerived {
+				derived = append(derived, u)
+
+				continue
+			}
+			found[u] = true
+			written[u.Name] = true
+		}
+	})
+	for _, u := range derived {
+		if !written[u.Name] {
+			found[u] = true
+		}
+	}
+
+	res := make([]UnresolvedVariable, 0, len(found))
+	for u := range found {
+		res = append(res, u)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Field != res[j].Field {
+			return res[i].Field < res[j].Field
+		}
+
+		return res[i].Name < res[j].Name
+	})
+
+	return res
+}
+
+// skippedFields are not checked: arguments of cases are substituted into fields of tests by the loader,
+// and arguments of other cases may use variables of those cases
+var skippedFields = map[string]bool{"cases": true}
+
+// visitStrings calls visit for all strings in value with paths of them. Structs without YAML tags are inlined,
+// fields without tags of structs with them are derived ones.
+func visitStrings(v reflect.Value, path string, derived bool, visit func(path, str string, derived bool)) {
+	switch v.Kind() {
+	case reflect.String:
+		visit(path, v.String(), derived)
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			visitStrings(v.Elem(), path, derived, visit)
+		}
+	case reflect.Struct:
+		tagged := hasYAMLTags(v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, inline, ok := yamlName(field)
+			switch {
+			case field.Anonymous || inline || !tagged:
+				visitStrings(v.Field(i), path, derived, visit)
+			case !ok:
+				visitStrings(v.Field(i), joinPath(path, lowerFirst(field.Name)), true, visit)
+			case name != "" && name != "-" && !skippedFields[name]:
+				visitStrings(v.Field(i), joinPath(path, name), derived, visit)
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			visitStrings(iter.Value(), joinPath(path, fmt.Sprint(iter.Key().Interface())), derived, visit)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			visitStrings(v.Index(i), fmt.Sprintf("%s[%d]", path, i), derived, visit)
+		}
+	}
+}
+
+// hasYAMLTags reports whether fields of the struct or of structs embedded into it have YAML tags
+func hasYAMLTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup("yaml"); ok {
+			return true
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && hasYAMLTags(field.Type) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func yamlName(field reflect.StructField) (name string, inline, ok bool) {
+	tag, ok := field.Tag.Lookup("yaml")
+	if !ok {
+		return "", false, false
+	}
+	name, options, _ := strings.Cut(tag, ",")
+
+	return name, strings.Contains(options, "inline"), true
+}
+
+func lowerFirst(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
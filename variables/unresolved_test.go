@@ -0,0 +1,33 @@
+package variables
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lamoda/gonkey/testloader/yaml_file"
+)
+
+func TestUnresolved(t *testing.T) {
+	vs := New()
+	vs.Set("host", "example.com")
+
+	test := &yaml_file.Test{
+		TestDefinition: yaml_file.TestDefinition{
+			RequestURL: "/users/{{ $id }}",
+			HeadersVal: map[string]string{"Authorization": "Bearer {{ $token }}", "Host": "{{ $host }}"},
+			MocksDefinition: map[string]interface{}{
+				"auth": map[interface{}]interface{}{"body": `{"token": "{{ $token | default "none" }}", "user": "{{ $user }}"}`},
+			},
+			Cases: []yaml_file.CaseData{{RequestArgs: map[string]interface{}{"other": "{{ $otherCase }}"}}},
+		},
+		Request: `{"id": "{{ $id }}", "name": "{{ $name | json }}"}`,
+	}
+
+	assert.Equal(t, []UnresolvedVariable{
+		{Name: "token", Field: "headers.Authorization"},
+		{Name: "user", Field: "mocks.auth.body"},
+		{Name: "id", Field: "path"},
+		{Name: "name", Field: "request"},
+	}, Unresolved(vs.Apply(test)))
+}
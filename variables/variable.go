@@ -1,9 +1,12 @@
 package variables
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 )
 
 type Variable struct {
@@ -11,22 +14,63 @@ type Variable struct {
 	value        string
 	defaultValue string
 	rx           *regexp.Regexp
+
+	// json is the value as JSON, variables taken from JSON keep its type: {"id": 1}, 42, "text"
+	json string
+
+	// scope and source tell where the value came from, they are shown in dump of variables
+	scope  Scope
+	source string
 }
 
 // NewVariable creates new variable with given name and value
 func NewVariable(name, value string) *Variable {
+	return newVariable(name, value, jsonString(value))
+}
+
+// NewJSONVariable creates variable with value given as JSON. {{ $name }} is replaced with the value
+// (strings without quotes, other values as JSON), {{ $name | json }} is replaced with JSON itself.
+func NewJSONVariable(name, raw string) (*Variable, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return nil, fmt.Errorf("invalid JSON value of variable '%s': %w", name, err)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(raw)); err != nil {
+		return nil, fmt.Errorf("invalid JSON value of variable '%s': %w", name, err)
+	}
+
+	if str, ok := value.(string); ok {
+		return newVariable(name, str, compact.String()), nil
+	}
+
+	return newVariable(name, compact.String(), compact.String()), nil
+}
+
+func newVariable(name, value, jsonValue string) *Variable {
 
 	name = regexp.QuoteMeta(name)
-	rx := regexp.MustCompile(fmt.Sprintf(`{{\s*\$%s\s*}}`, name))
+	rx := regexp.MustCompile(fmt.Sprintf(`{{\s*\$(%s)\s*(%s)}}`, name, filtersPattern))
 
 	return &Variable{
 		name:         name,
 		value:        value,
 		defaultValue: value,
 		rx:           rx,
+		json:         jsonValue,
 	}
 }
 
+// scoped returns copy of the variable with given name in the scope
+func (v *Variable) scoped(name string, scope Scope, source string) *Variable {
+	res := newVariable(name, v.value, v.json)
+	res.scope = scope
+	res.source = source
+
+	return res
+}
+
 func NewFromEnvironment(name string) *Variable {
 	val := os.Getenv(name)
 	if val == "" {
@@ -39,11 +83,24 @@ func NewFromEnvironment(name string) *Variable {
 // perform replaces variable in str to its value
 // and returns result string
 func (v *Variable) Perform(str string) string {
+	return v.rx.ReplaceAllStringFunc(str, func(match string) string {
+		value, _ := parseExpression(v.rx.FindStringSubmatch(match)).evaluate(v)
+
+		return value
+	})
+}
+
+// JSON returns the value of variable as JSON
+func (v *Variable) JSON() string {
+	return v.json
+}
 
-	res := v.rx.ReplaceAllLiteral(
-		[]byte(str),
-		[]byte(v.value),
-	)
+// jsonString returns string as JSON without escaping of HTML characters
+func jsonString(str string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(str)
 
-	return string(res)
+	return strings.TrimSuffix(buf.String(), "\n")
 }
@@ -1,189 +1,387 @@
 package variables
 
 import (
-	"regexp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/lamoda/gonkey/models"
 )
 
+// Scope is the part of the run variable is visible in
+type Scope string
+
+const (
+	// ScopeSuite variables are visible in all tests of the run
+	ScopeSuite Scope = "suite"
+	// ScopeFile variables are visible in tests of one file, they are defined in the file header
+	// or taken from responses of the tests of the file
+	ScopeFile Scope = "file"
+	// ScopeTest variables are visible in all cases of the test
+	ScopeTest Scope = "test"
+	// ScopeCase variables are visible in one case of the test
+	ScopeCase Scope = "case"
+)
+
+// GlobalPrefix marks variables of test files which are stored in suite scope: global:token
+const GlobalPrefix = "global:"
+
+// Variables are looked up in case, test, file and suite scopes in this order, then in environment
 type Variables struct {
-	variables variables
+	variables variables // suite scope
+	file      string
+	fileVars  variables
+	testVars  variables
+	caseVars  variables
+	generator *generator
+
+	// secrets are names of variables whose values are masked in outputs, secretValues are values of them
+	// seen during the run with their names, they stay masked when variables go out of scope
+	secrets      map[string]bool
+	secretValues map[string]string
 }
 
 type variables map[string]*Variable
 
-var variableRx = regexp.MustCompile(`{{\s*\$(\w+)\s*}}`)
-
+// New creates empty set of variables, generator functions are seeded with current time
 func New() *Variables {
 	return &Variables{
 		variables: make(variables),
+		fileVars:  make(variables),
+		testVars:  make(variables),
+		caseVars:  make(variables),
+		generator: newGenerator(time.Now().UnixNano()),
+
+		secrets:      make(map[string]bool),
+		secretValues: make(map[string]string),
 	}
 }
 
-// Load adds new variables and replaces values of existing
-func (vs *Variables) Load(variables map[string]string) {
-	for n, v := range variables {
-		variable := NewVariable(n, v)
+// SetSeed makes values of generator functions ($uuid(), $randInt(1, 10), etc.) reproducible
+func (vs *Variables) SetSeed(seed int64) {
+	vs.generator = newGenerator(seed)
+}
 
-		vs.variables[n] = variable
+// Seed returns seed of generator functions, it can be passed to SetSeed to replay the run
+func (vs *Variables) Seed() int64 {
+	return vs.generator.seed
+}
+
+// Generate returns copy of values with calls of generator functions replaced with generated values,
+// so the values stay the same in all fields of the test they are used in
+func (vs *Variables) Generate(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
 	}
+
+	// names are sorted, so variables with the same template get the same values on replay
+	names := make([]string, 0, len(values))
+	for n := range values {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	res := make(map[string]string, len(values))
+	for _, n := range names {
+		res[n] = vs.generator.generate(values[n])
+	}
+
+	return res
 }
 
-// Load adds new variables and replaces values of existing
-func (vs *Variables) Set(name, value string) {
-	v := NewVariable(name, value)
+// EnterFile makes file scope belong to the given test file. When the file differs from the previous one,
+// variables of the previous file are dropped and variables of the file header are loaded.
+func (vs *Variables) EnterFile(filename string, values map[string]string) {
+	if filename == vs.file {
+		return
+	}
 
-	vs.variables[name] = v
+	vs.file = filename
+	vs.fileVars = make(variables)
+	vs.loadScoped(vs.fileVars, ScopeFile, vs.Generate(values), "header of "+filename)
 }
 
-func (vs *Variables) Apply(t models.TestInterface) models.TestInterface {
-	newTest := t.Clone()
+// EnterTest replaces test and case scopes with variables of the test and its case,
+// generator functions in them are evaluated once, so all fields of the test get the same values
+func (vs *Variables) EnterTest(name string, values, caseValues map[string]string) {
+	vs.testVars = make(variables)
+	vs.caseVars = make(variables)
+	vs.loadScoped(vs.testVars, ScopeTest, vs.Generate(values), fmt.Sprintf("test %q", name))
+	vs.loadScoped(vs.caseVars, ScopeCase, vs.Generate(caseValues), fmt.Sprintf("case %q", name))
+}
 
-	if vs == nil {
-		return newTest
+// SetFromTest stores variables taken from results of the test (variables_to_set, captures) in file scope,
+// so they are visible in the following tests of the same file. Names with global: prefix are stored in suite scope.
+func (vs *Variables) SetFromTest(vars *Variables, source string) {
+	if vars == nil {
+		return
 	}
 
-	newTest.SetQuery(vs.perform(newTest.ToQuery()))
-	newTest.SetMethod(vs.perform(newTest.GetMethod()))
-	newTest.SetPath(vs.perform(newTest.Path()))
-	newTest.SetRequest(vs.perform(newTest.GetRequest()))
-	newTest.SetDbQueryString(vs.perform(newTest.DbQueryString()))
-	newTest.SetDbResponseJson(vs.performDbResponses(newTest.DbResponseJson()))
+	for n, v := range vars.variables {
+		vs.setScoped(vs.fileVars, ScopeFile, n, v, source)
+	}
+}
 
-	dbChecks := []models.DatabaseCheck{}
-	for _, def := range newTest.GetDatabaseChecks() {
-		def.SetDbQueryString(vs.perform(def.DbQueryString()))
-		def.SetDbResponseJson(vs.performDbResponses(def.DbResponseJson()))
-		dbChecks = append(dbChecks, def)
+// loadScoped stores values in the given scope, names with global: prefix are stored in suite scope
+func (vs *Variables) loadScoped(scope variables, name Scope, values map[string]string, source string) {
+	for n, value := range values {
+		vs.setScoped(scope, name, n, NewVariable(n, value), source)
 	}
-	newTest.SetDatabaseChecks(dbChecks)
+}
 
-	newTest.SetResponses(vs.performResponses(newTest.GetResponses()))
-	newTest.SetHeaders(vs.performHeaders(newTest.Headers()))
+func (vs *Variables) setScoped(scope variables, name Scope, n string, v *Variable, source string) {
+	if global, ok := strings.CutPrefix(n, GlobalPrefix); ok {
+		vs.variables[global] = v.scoped(global, ScopeSuite, source)
 
-	if form := newTest.GetForm(); form != nil {
-		newTest.SetForm(vs.performForm(form))
+		return
 	}
+	scope[n] = v.scoped(n, name, source)
+}
 
-	for _, definition := range newTest.ServiceMocks() {
-		vs.performInterface(definition)
+// Dump lists visible variables with their values, scopes and origins, one variable per line.
+// Values of secret variables are masked.
+func (vs *Variables) Dump() string {
+	visible := make(variables)
+	for _, scope := range []variables{vs.variables, vs.fileVars, vs.testVars, vs.caseVars} {
+		for n, v := range scope {
+			visible[n] = v
+		}
 	}
 
-	return newTest
+	names := make([]string, 0, len(visible))
+	for n := range visible {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, n := range names {
+		v := visible[n]
+		value := v.value
+		if vs.isSecret(n) {
+			value = Redacted
+		}
+		scope := v.scope
+		if scope == "" {
+			scope = ScopeSuite
+		}
+		if v.source == "" {
+			fmt.Fprintf(&b, "%s = %q (%s)\n", n, value, scope)
+		} else {
+			fmt.Fprintf(&b, "%s = %q (%s, %s)\n", n, value, scope, v.source)
+		}
+	}
+
+	return b.String()
 }
 
-// Merge adds given variables to set or overrides existed
-func (vs *Variables) Merge(vars *Variables) {
-	for k, v := range vars.variables {
-		vs.variables[k] = v
+// Load adds new variables to suite scope and replaces values of existing
+func (vs *Variables) Load(variables map[string]string) {
+	for n, v := range variables {
+		variable := NewVariable(n, v)
+
+		vs.variables[n] = variable
 	}
 }
 
-func (vs *Variables) Len() int {
-	return len(vs.variables)
+// Set adds new variable to suite scope or replaces value of existing
+func (vs *Variables) Set(name, value string) {
+	v := NewVariable(name, value)
+
+	vs.variables[name] = v
 }
 
-func usedVariables(str string) (res []string) {
-	matches := variableRx.FindAllStringSubmatch(str, -1)
-	for _, match := range matches {
-		res = append(res, match[1])
+// Apply returns copy of the test with variables substituted in all its strings: exported fields of structs,
+// values of maps, elements of slices and values behind pointers and interfaces are processed recursively,
+// so new fields of the test get substitution without changes here
+func (vs *Variables) Apply(t models.TestInterface) models.TestInterface {
+	if vs == nil {
+		return t.Clone()
 	}
 
-	return res
+	newTest := vs.performValue(reflect.ValueOf(t)).Interface().(models.TestInterface)
+	// query is set again to get ? prefix added by the setter
+	newTest.SetQuery(newTest.ToQuery())
+
+	return newTest
 }
 
-// perform replaces all variables in str to their values
-// and returns result string
-func (vs *Variables) perform(str string) string {
-	varNames := usedVariables(str)
+// performValue returns copy of value with variables substituted in all strings it contains,
+// keys of maps and unexported fields of structs are copied as is
+func (vs *Variables) performValue(v reflect.Value) reflect.Value {
+	return replaceValue(v, vs.perform, vs.performJSONValue)
+}
 
-	for _, k := range varNames {
-		if v := vs.get(k); v != nil {
-			str = v.Perform(str)
+// replaceValue returns copy of value with str applied to all strings it contains, byte slices are processed
+// as strings. typed may replace values behind interfaces with values of other types.
+func replaceValue(v reflect.Value, str func(string) string, typed func(reflect.Value) (interface{}, bool)) reflect.Value {
+	switch v.Kind() {
+	case reflect.String:
+		res := reflect.New(v.Type()).Elem()
+		res.SetString(str(v.String()))
+
+		return res
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
 		}
-	}
+		res := reflect.New(v.Type().Elem())
+		res.Elem().Set(replaceValue(v.Elem(), str, typed))
 
-	return str
-}
+		return res
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Type()).Elem()
+		if typed != nil {
+			if value, ok := typed(v.Elem()); ok && reflect.TypeOf(value).AssignableTo(v.Type()) {
+				res.Set(reflect.ValueOf(value))
 
-func (vs *Variables) performInterface(value interface{}) {
-	if mapValue, ok := value.(map[interface{}]interface{}); ok {
-		for key := range mapValue {
-			if strValue, ok := mapValue[key].(string); ok {
-				mapValue[key] = vs.perform(strValue)
-			} else {
-				vs.performInterface(mapValue[key])
+				return res
 			}
 		}
-	}
-	if arrValue, ok := value.([]interface{}); ok {
-		for idx := range arrValue {
-			if strValue, ok := arrValue[idx].(string); ok {
-				arrValue[idx] = vs.perform(strValue)
-			} else {
-				vs.performInterface(arrValue[idx])
+		res.Set(replaceValue(v.Elem(), str, typed))
+
+		return res
+	case reflect.Struct:
+		res := reflect.New(v.Type()).Elem()
+		res.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := res.Field(i); field.CanSet() {
+				field.Set(replaceValue(v.Field(i), str, typed))
 			}
 		}
+
+		return res
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			res.SetMapIndex(iter.Key(), replaceValue(iter.Value(), str, typed))
+		}
+
+		return res
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return reflect.ValueOf([]byte(str(string(v.Bytes())))).Convert(v.Type())
+		}
+		res := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res.Index(i).Set(replaceValue(v.Index(i), str, typed))
+		}
+
+		return res
+	default:
+		return v
 	}
 }
 
-func (vs *Variables) get(name string) *Variable {
-	v := vs.variables[name]
-	if v == nil {
-		v = NewFromEnvironment(name)
+// Merge adds given variables to suite scope or overrides existed
+func (vs *Variables) Merge(vars *Variables) {
+	for k, v := range vars.variables {
+		vs.variables[k] = v
 	}
-
-	return v
 }
 
-func (vs *Variables) performForm(form *models.Form) *models.Form {
-	files := make(map[string]string, len(form.Files))
+func (vs *Variables) Len() int {
+	return len(vs.variables)
+}
 
-	for k, v := range form.Files {
-		files[k] = vs.perform(v)
+// performJSONValue returns typed value of variable for strings like {{ $name | json }} or {{ $name | default "x" | json }},
+// maps of objects have interface{} keys and integers are int, as in values decoded from YAML
+func (vs *Variables) performJSONValue(v reflect.Value) (interface{}, bool) {
+	if v.Kind() != reflect.String {
+		return nil, false
+	}
+	match := jsonValueRx.FindStringSubmatch(v.String())
+	if match == nil {
+		return nil, false
+	}
+	expr := parseExpression(match)
+	if !expr.json {
+		return nil, false
+	}
+	raw, ok := expr.evaluate(vs.get(expr.name))
+	if !ok {
+		return nil, false
 	}
 
-	fields := make(map[string]string, len(form.Fields))
-
-	for k, v := range form.Fields {
-		fields[k] = vs.perform(v)
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || value == nil {
+		return nil, false
 	}
 
-	return &models.Form{Files: files, Fields: fields}
+	return yamlValue(value), true
 }
 
-func (vs *Variables) performHeaders(headers map[string]string) map[string]string {
-	res := make(map[string]string)
-
-	for k, v := range headers {
-		res[k] = vs.perform(v)
-	}
+func yamlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := make(map[interface{}]interface{}, len(v))
+		for key, item := range v {
+			res[key] = yamlValue(item)
+		}
 
-	return res
-}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = yamlValue(item)
+		}
 
-func (vs *Variables) performResponses(responses map[int]string) map[int]string {
-	res := make(map[int]string)
+		return res
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n)
+		}
+		f, _ := v.Float64()
 
-	for k, v := range responses {
-		res[k] = vs.perform(v)
+		return f
+	default:
+		return v
 	}
+}
 
-	return res
+// Perform replaces all variables and calls of generator functions in str with their values
+func (vs *Variables) Perform(str string) string {
+	return vs.perform(str)
 }
 
-func (vs *Variables) performDbResponses(responses []string) []string {
-	if responses == nil {
-		return nil
-	}
+// perform replaces all variables in str to their values
+// and returns result string, variables which are not defined and have no default value are left as is
+func (vs *Variables) perform(str string) string {
+	str = expressionRx.ReplaceAllStringFunc(str, func(match string) string {
+		expr := parseExpression(expressionRx.FindStringSubmatch(match))
+		if value, ok := expr.evaluate(vs.get(expr.name)); ok {
+			return value
+		}
+
+		return match
+	})
 
-	res := make([]string, len(responses))
+	return vs.generator.generate(str)
+}
 
-	for idx, v := range responses {
-		res[idx] = vs.perform(v)
+func (vs *Variables) get(name string) *Variable {
+	for _, scope := range []variables{vs.caseVars, vs.testVars, vs.fileVars, vs.variables} {
+		if v := scope[name]; v != nil {
+			return v
+		}
 	}
 
-	return res
+	return NewFromEnvironment(name)
 }
 
 func (vs *Variables) Add(v *Variable) *Variables {
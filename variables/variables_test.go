@@ -0,0 +1,166 @@
+package variables
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopes(t *testing.T) {
+	vs := New()
+	vs.Load(map[string]string{"host": "suite", "name": "suite"})
+
+	vs.EnterFile("a.yaml", map[string]string{"name": "file", "global:shared": "header"})
+	vs.EnterTest("first", map[string]string{"name": "test", "id": "1"}, map[string]string{"id": "2"})
+	assert.Equal(t, "suite test 2 header", vs.perform("{{ $host }} {{ $name }} {{ $id }} {{ $shared }}"))
+
+	response := New().Add(NewVariable("token", "abc")).Add(NewVariable("global:session", "s1"))
+	vs.SetFromTest(response, `variables_to_set of test "first"`)
+
+	// test and case variables are dropped, file variables stay in the same file
+	vs.EnterTest("second", nil, nil)
+	assert.Equal(t, "file abc s1", vs.perform("{{ $name }} {{ $token }} {{ $session }}"))
+	assert.Equal(t, "{{ $id }}", vs.perform("{{ $id }}"))
+
+	// file variables do not leak into another file, global ones do
+	vs.EnterFile("b.yaml", nil)
+	vs.EnterTest("third", nil, nil)
+	assert.Equal(t, "suite {{ $token }} s1 header", vs.perform("{{ $name }} {{ $token }} {{ $session }} {{ $shared }}"))
+}
+
+func TestEnterSameFileKeepsVariables(t *testing.T) {
+	vs := New()
+	vs.EnterFile("a.yaml", map[string]string{"id": "{{ $randInt(1, 1000000) }}"})
+	id := vs.perform("{{ $id }}")
+
+	vs.SetFromTest(New().Add(NewVariable("token", "abc")), "")
+	vs.EnterFile("a.yaml", map[string]string{"id": "{{ $randInt(1, 1000000) }}"})
+	assert.Equal(t, id+" abc", vs.perform("{{ $id }} {{ $token }}"))
+}
+
+func TestDump(t *testing.T) {
+	vs := New()
+	vs.Set("host", "localhost")
+	vs.EnterFile("a.yaml", map[string]string{"name": "file"})
+	vs.EnterTest("get user", map[string]string{"name": "test"}, map[string]string{"lang": "en"})
+	vs.SetFromTest(New().Add(NewVariable("global:token", "abc")), `variables_to_set of test "log in"`)
+
+	assert.Equal(t, `host = "localhost" (suite)
+lang = "en" (case, case "get user")
+name = "test" (test, test "get user")
+token = "abc" (suite, variables_to_set of test "log in")
+`, vs.Dump())
+}
+
+func TestPerformValue(t *testing.T) {
+	type header string
+	type nested struct {
+		Values  []string
+		Headers map[string]header
+		Any     interface{}
+		private string
+	}
+	type value struct {
+		Name   string
+		Nested *nested
+		Count  int
+	}
+
+	vs := New()
+	vs.Set("id", "42")
+
+	original := value{
+		Name: "user {{ $id }}",
+		Nested: &nested{
+			Values:  []string{"{{ $id }}"},
+			Headers: map[string]header{"{{ $id }}": "id {{ $id }}"},
+			Any:     map[interface{}]interface{}{"list": []interface{}{"{{ $id }}", 1}},
+			private: "{{ $id }}",
+		},
+		Count: 1,
+	}
+
+	res := vs.performValue(reflect.ValueOf(original)).Interface().(value)
+	assert.Equal(t, value{
+		Name: "user 42",
+		Nested: &nested{
+			Values:  []string{"42"},
+			Headers: map[string]header{"{{ $id }}": "id 42"},
+			Any:     map[interface{}]interface{}{"list": []interface{}{"42", 1}},
+			private: "{{ $id }}",
+		},
+		Count: 1,
+	}, res)
+	assert.Equal(t, "user {{ $id }}", original.Name)
+	assert.Equal(t, []string{"{{ $id }}"}, original.Nested.Values)
+}
+
+func TestJSONSubstitution(t *testing.T) {
+	vars, err := FromResponse(map[string]string{
+		"user":  "user",
+		"count": "count",
+		"name":  "user.name",
+		"tags":  "tags",
+	}, `{"user": {"name": "<John>", "age": 42}, "count": 3, "tags": ["a", "b"]}`, true)
+	require.NoError(t, err)
+
+	vs := New()
+	vs.SetFromTest(vars, "")
+
+	assert.Equal(t,
+		`{"user": {"name": "<John>", "age": 42}, "count": 3, "name": "<John>", "tags": ["a", "b"]}`,
+		vs.Perform(`{"user": {{ $user | json }}, "count": {{$count|json}}, "name": {{ $name | json }}, "tags": {{ $tags | json }}}`),
+	)
+	assert.Equal(t, `<John> 3`, vs.Perform(`{{ $name }} {{ $count }}`))
+
+	vs.Set("plain", `say "hi"`)
+	assert.Equal(t, `"say \"hi\""`, vs.Perform(`{{ $plain | json }}`))
+
+	captured, err := FromCaptures(map[string]interface{}{"ids": []interface{}{1.0, 2.0}})
+	require.NoError(t, err)
+	vs.SetFromTest(captured, "")
+	assert.Equal(t, `[1,2] [1,2]`, vs.Perform(`{{ $ids }} {{ $ids | json }}`))
+}
+
+func TestPerformValueKeepsJSONTypes(t *testing.T) {
+	vs := New()
+	user, err := NewJSONVariable("user", `{"name": "John", "age": 42, "score": 1.5}`)
+	require.NoError(t, err)
+	vs.Add(user)
+
+	res := vs.performValue(reflect.ValueOf(map[string]interface{}{
+		"user":  "{{ $user | json }}",
+		"name":  "{{ $user }}",
+		"other": "{{ $unknown | json }}",
+	})).Interface()
+
+	assert.Equal(t, map[string]interface{}{
+		"user":  map[interface{}]interface{}{"name": "John", "age": 42, "score": 1.5},
+		"name":  `{"name":"John","age":42,"score":1.5}`,
+		"other": "{{ $unknown | json }}",
+	}, res)
+
+	_, err = NewJSONVariable("invalid", `{"name":`)
+	assert.Error(t, err)
+}
+
+func TestDefaultFilter(t *testing.T) {
+	vs := New()
+	vs.Set("name", "John")
+	t.Setenv("GONKEY_TEST_REGION", "eu")
+
+	assert.Equal(t, "John", vs.Perform(`{{ $name | default "anonymous" }}`))
+	assert.Equal(t, "anonymous", vs.Perform(`{{ $unknown | default "anonymous" }}`))
+	assert.Equal(t, "eu", vs.Perform(`{{ $GONKEY_TEST_REGION | default "us" }}`), "environment is checked before default")
+	assert.Equal(t, "", vs.Perform(`{{ $unknown | default "" }}`))
+	assert.Equal(t, `say "hi"`, vs.Perform(`{{ $unknown|default "say \"hi\"" }}`))
+	assert.Equal(t, `"anonymous" "John"`, vs.Perform(`{{ $unknown | default "anonymous" | json }} {{ $name | default "x" | json }}`))
+	assert.Equal(t, "{{ $unknown }}", vs.Perform("{{ $unknown }}"), "variables without default are left as is")
+
+	res := vs.performValue(reflect.ValueOf(map[string]interface{}{
+		"count": `{{ $count | default "3" | json }}`,
+	})).Interface()
+	assert.Equal(t, map[string]interface{}{"count": "3"}, res, "default value is a string")
+}
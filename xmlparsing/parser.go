@@ -2,6 +2,7 @@ package xmlparsing
 
 import (
 	"encoding/xml"
+	"strings"
 )
 
 func Parse(rawXML string) (map[string]interface{}, error) {
@@ -48,39 +49,51 @@ func buildArray(nodes []node) []interface{} {
 }
 
 func buildNode(n node) interface{} {
-	hasAttrs := len(n.Attrs) > 0
+	attrs := buildAttributes(n.Attrs)
+	hasAttrs := len(attrs) > 0
 	hasChildren := len(n.Children) > 0
 
-	if hasAttrs && hasChildren {
+	if hasChildren {
 		result := buildMap(n.Children)
-		result["-attrs"] = buildAttributes(n.Attrs)
+		if hasAttrs {
+			result["-attrs"] = attrs
+		}
+		// text of mixed content, whitespace between child elements is not significant
+		if text := strings.TrimSpace(n.Content); text != "" {
+			result["content"] = text
+		}
 
 		return result
 	}
 
 	if hasAttrs {
 		return map[string]interface{}{
-			"-attrs":  buildAttributes(n.Attrs),
+			"-attrs":  attrs,
 			"content": n.Content,
 		}
 	}
 
-	if hasChildren {
-		return buildMap(n.Children)
-	}
-
 	return n.Content
 }
 
+// buildAttributes skips namespace declarations: names of elements and attributes are already
+// qualified with namespace URIs, so documents using different prefixes for them are equal
 func buildAttributes(attrs []xml.Attr) map[string]string {
 	m := make(map[string]string, len(attrs))
 	for _, attr := range attrs {
+		if isNamespaceDeclaration(attr.Name) {
+			continue
+		}
 		m[joinXMLName(attr.Name)] = attr.Value
 	}
 
 	return m
 }
 
+func isNamespaceDeclaration(name xml.Name) bool {
+	return name.Space == "xmlns" || (name.Space == "" && name.Local == "xmlns")
+}
+
 func regroupNodesByName(nodes []node) map[string][]node {
 	grouped := make(map[string][]node)
 	for _, n := range nodes {
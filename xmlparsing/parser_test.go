@@ -104,6 +104,41 @@ var parseTestCases = []parseTestCase{
 		}
 		`,
 	},
+	{
+		name: "TestCase#5_declared_namespaces",
+		rawXml: `
+		<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:m="urn:shop">
+			<soap:Body>
+				<m:Price currency="USD" m:type="retail">10</m:Price>
+				<Total xmlns="urn:shop">10</Total>
+			</soap:Body>
+		</soap:Envelope>
+		`,
+		expectedJson: `{
+			"http://schemas.xmlsoap.org/soap/envelope/:Envelope": {
+				"http://schemas.xmlsoap.org/soap/envelope/:Body": {
+					"urn:shop:Price": {
+						"-attrs": {"currency": "USD", "urn:shop:type": "retail"},
+						"content": "10"
+					},
+					"urn:shop:Total": "10"
+				}
+			}
+		}
+		`,
+	},
+	{
+		name:   "TestCase#6_mixed_content",
+		rawXml: `<p class="note">Hello, <b>world</b>!</p>`,
+		expectedJson: `{
+			"p": {
+				"-attrs": {"class": "note"},
+				"b": "world",
+				"content": "Hello, !"
+			}
+		}
+		`,
+	},
 }
 
 func TestParse(t *testing.T) {
@@ -111,3 +146,14 @@ func TestParse(t *testing.T) {
 		t.Run(tc.name, tc.runTest)
 	}
 }
+
+func TestParseIsPrefixIndependent(t *testing.T) {
+	first, err := Parse(`<a:order xmlns:a="urn:shop"><a:item>1</a:item><a:item>2</a:item></a:order>`)
+	assert.NoError(t, err)
+
+	second, err := Parse(`<order xmlns="urn:shop"><item>1</item><item>2</item></order>`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, []interface{}{"1", "2"}, first["urn:shop:order"].(map[string]interface{})["urn:shop:item"])
+}
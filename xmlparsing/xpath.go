@@ -0,0 +1,137 @@
+package xmlparsing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+)
+
+// Document is a parsed XML document for XPath queries
+type Document struct {
+	root *xmlquery.Node
+}
+
+// ParseDocument parses XML document for XPath queries
+func ParseDocument(rawXML string) (*Document, error) {
+	root, err := xmlquery.Parse(strings.NewReader(rawXML))
+	if err != nil {
+		return nil, err
+	}
+
+	if xmlquery.FindOne(root, "/*") == nil {
+		return nil, errors.New("document has no root element")
+	}
+
+	return &Document{root: root}, nil
+}
+
+// ValidateXPath checks that XPath expression can be compiled with given namespaces
+func ValidateXPath(expr string, namespaces map[string]string) error {
+	if _, err := xpath.CompileWithNS(expr, namespaces); err != nil {
+		return fmt.Errorf("invalid xpath %s: %w", expr, err)
+	}
+
+	return nil
+}
+
+// XPathResult is a result of XPath expression
+type XPathResult struct {
+	// Nodes contains string values of nodes selected by the expression
+	Nodes []string
+	// Value contains float64, string or bool result of the expression not selecting nodes (count(), etc.)
+	Value interface{}
+
+	isNodeSet bool
+}
+
+// Query evaluates XPath expression. Prefixes used in the expression are resolved with namespaces
+// (prefix to URI), so they do not have to be equal to prefixes used in the document.
+func (d *Document) Query(expr string, namespaces map[string]string) (*XPathResult, error) {
+	// expressions are compiled for each query as compiled ones can not be evaluated concurrently
+	compiled, err := xpath.CompileWithNS(expr, namespaces)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xpath %s: %w", expr, err)
+	}
+
+	value := compiled.Evaluate(xmlquery.CreateXPathNavigator(d.root))
+
+	iter, ok := value.(*xpath.NodeIterator)
+	if !ok {
+		return &XPathResult{Value: value}, nil
+	}
+
+	res := &XPathResult{Nodes: []string{}, isNodeSet: true}
+	for iter.MoveNext() {
+		res.Nodes = append(res.Nodes, iter.Current().Value())
+	}
+
+	return res, nil
+}
+
+// Exists reports whether the expression selects at least one node.
+// Expressions which do not select nodes always exist.
+func (r *XPathResult) Exists() bool {
+	return !r.isNodeSet || len(r.Nodes) > 0
+}
+
+// String returns string value of the result the way XPath string() function does,
+// the string value of the first node is used for node sets.
+func (r *XPathResult) String() string {
+	if r.isNodeSet {
+		if len(r.Nodes) == 0 {
+			return ""
+		}
+
+		return r.Nodes[0]
+	}
+
+	return xpathString(r.Value)
+}
+
+// Comparable returns expected value and the result shaped to be compared with each other.
+// Results are compared as strings: the expected value is converted to string the way XPath does
+// and compared with String(). If the expected value is a list, its elements are compared with
+// all selected nodes.
+func (r *XPathResult) Comparable(expected interface{}) (expectedValue, actualValue interface{}) {
+	list, ok := expected.([]interface{})
+	if !ok {
+		return xpathString(expected), r.String()
+	}
+
+	expectedList := make([]interface{}, len(list))
+	for i, v := range list {
+		expectedList[i] = xpathString(v)
+	}
+
+	actualList := make([]interface{}, 0, len(r.Nodes))
+	if !r.isNodeSet {
+		actualList = append(actualList, r.String())
+	}
+	for _, node := range r.Nodes {
+		actualList = append(actualList, node)
+	}
+
+	return expectedList, actualList
+}
+
+func xpathString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		if math.IsNaN(v) {
+			return "NaN"
+		}
+
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,87 @@
+package xmlparsing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const soapResponse = `
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+	<soap:Body>
+		<GetPriceResponse xmlns="urn:shop">
+			<Price currency="USD">10</Price>
+			<Price currency="EUR">9.5</Price>
+		</GetPriceResponse>
+	</soap:Body>
+</soap:Envelope>
+`
+
+var shopNamespaces = map[string]string{
+	"s": "http://schemas.xmlsoap.org/soap/envelope/",
+	"m": "urn:shop",
+}
+
+func TestDocumentQuery(t *testing.T) {
+	doc, err := ParseDocument(soapResponse)
+	require.NoError(t, err)
+
+	tests := []struct {
+		expr   string
+		str    string
+		nodes  []string
+		exists bool
+	}{
+		{expr: "/s:Envelope/s:Body/m:GetPriceResponse/m:Price", str: "10", nodes: []string{"10", "9.5"}, exists: true},
+		{expr: "//m:Price/@currency", str: "USD", nodes: []string{"USD", "EUR"}, exists: true},
+		{expr: "//m:Discount", str: "", nodes: []string{}, exists: false},
+		{expr: "count(//m:Price)", str: "2", exists: true},
+		{expr: "sum(//m:Price)", str: "19.5", exists: true},
+		{expr: "//m:Price[@currency='EUR'] = 9.5", str: "true", exists: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			res, err := doc.Query(tt.expr, shopNamespaces)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.str, res.String())
+			assert.Equal(t, tt.nodes, res.Nodes)
+			assert.Equal(t, tt.exists, res.Exists())
+		})
+	}
+}
+
+func TestDocumentQueryErrors(t *testing.T) {
+	_, err := ParseDocument(`{"a": 1}`)
+	assert.Error(t, err)
+
+	doc, err := ParseDocument(soapResponse)
+	require.NoError(t, err)
+
+	_, err = doc.Query("//x:Price", shopNamespaces)
+	assert.EqualError(t, err, "invalid xpath //x:Price: prefix x not defined.")
+}
+
+func TestXPathResultComparable(t *testing.T) {
+	doc, err := ParseDocument(soapResponse)
+	require.NoError(t, err)
+
+	res, err := doc.Query("//m:Price", shopNamespaces)
+	require.NoError(t, err)
+
+	expected, actual := res.Comparable(10)
+	assert.Equal(t, "10", expected)
+	assert.Equal(t, "10", actual)
+
+	expected, actual = res.Comparable([]interface{}{10, 9.5})
+	assert.Equal(t, []interface{}{"10", "9.5"}, expected)
+	assert.Equal(t, []interface{}{"10", "9.5"}, actual)
+
+	res, err = doc.Query("count(//m:Price) > 1", shopNamespaces)
+	require.NoError(t, err)
+
+	expected, actual = res.Comparable(true)
+	assert.Equal(t, expected, actual)
+}